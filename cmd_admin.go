@@ -0,0 +1,322 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/registration"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/term"
+)
+
+// parseFlags parses command flags, turning parse failures into usage errors.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return usageError{fmt.Sprintf("%s: %v", fs.Name(), err)}
+	}
+	return nil
+}
+
+// readPassword prompts for a password on the terminal, asking twice when confirm
+// is set. When stdin is not a terminal the password is read from its first line,
+// so scripts can pipe it in.
+func readPassword(prompt string, confirm bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		password := strings.TrimRight(line, "\r\n")
+		if password == "" {
+			return "", errors.New("no password provided on stdin")
+		}
+		return password, nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if len(password) == 0 {
+		return "", errors.New("password must not be empty")
+	}
+
+	if confirm {
+		fmt.Fprint(os.Stderr, "Confirm password: ")
+		again, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		if string(again) != string(password) {
+			return "", errors.New("passwords do not match")
+		}
+	}
+
+	return string(password), nil
+}
+
+func runCreateSuperAdmin(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("create-super-admin", flag.ContinueOnError)
+	email := fs.String("email", "", "email address (required)")
+	name := fs.String("name", "", "full name (required)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *email == "" || *name == "" {
+		return usageError{"usage: unibook create-super-admin -email <email> -name <full name>"}
+	}
+
+	password, err := readPassword("Password: ", true)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+	if err != nil {
+		return err
+	}
+
+	database.Connect(cfg.DatabaseURL, false)
+	defer database.DB.Close()
+
+	admin, err := db.New(database.DB).CreateSuperAdmin(context.Background(), db.CreateSuperAdminParams{
+		FullName:     *name,
+		Email:        strings.ToLower(strings.TrimSpace(*email)),
+		PasswordHash: string(hashedPassword),
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return fmt.Errorf("a super admin with email %s already exists", *email)
+		}
+		return err
+	}
+
+	fmt.Printf("Created super admin %s (%s)\n", admin.Email, admin.ID)
+	return nil
+}
+
+func runResetPassword(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	email := fs.String("email", "", "email address of the user or super admin (required)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return usageError{"usage: unibook reset-password -email <email>"}
+	}
+
+	database.Connect(cfg.DatabaseURL, false)
+	defer database.DB.Close()
+
+	ctx := context.Background()
+	queries := db.New(database.DB)
+
+	superAdmin, superAdminErr := queries.GetSuperAdminByEmail(ctx, *email)
+	user, userErr := queries.GetUserByEmail(ctx, *email)
+	if superAdminErr != nil && userErr != nil {
+		if errors.Is(superAdminErr, pgx.ErrNoRows) && errors.Is(userErr, pgx.ErrNoRows) {
+			return fmt.Errorf("no account found for %s", *email)
+		}
+		return errors.Join(superAdminErr, userErr)
+	}
+
+	password, err := readPassword("New password: ", true)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+	if err != nil {
+		return err
+	}
+
+	if superAdminErr == nil {
+		err = queries.UpdateSuperAdminPassword(ctx, db.UpdateSuperAdminPasswordParams{
+			ID:           superAdmin.ID,
+			PasswordHash: string(hashedPassword),
+		})
+		if err == nil {
+			err = queries.RevokeUserSessions(ctx, superAdmin.ID)
+		}
+	} else {
+		err = queries.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+			ID:           user.ID,
+			PasswordHash: string(hashedPassword),
+		})
+		if err == nil {
+			err = queries.RevokeUserSessions(ctx, user.ID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Password updated for %s; existing sessions were revoked\n", *email)
+	return nil
+}
+
+func runListColleges(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("list-colleges", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	database.Connect(cfg.DatabaseURL, false)
+	defer database.DB.Close()
+
+	colleges, err := db.New(database.DB).ListColleges(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tDOMAIN\tPAID")
+	for _, college := range colleges {
+		domain := "-"
+		if college.DomainName.Valid {
+			domain = college.DomainName.String
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", college.ID, college.Name, domain, college.HasPaid)
+	}
+	return w.Flush()
+}
+
+func runPurgeExpiredOtps(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("purge-expired-otps", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	database.Connect(cfg.DatabaseURL, false)
+	defer database.DB.Close()
+
+	now := time.Now()
+	deleted, err := db.New(database.DB).DeleteExpiredOtps(context.Background(), db.DeleteExpiredOtpsParams{
+		Now:          pgtype.Timestamp{Time: now, Valid: true},
+		LockedBefore: pgtype.Timestamp{Time: now.Add(-cfg.OTPLockout), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted %d expired or used OTPs\n", deleted)
+	return nil
+}
+
+func runProcessWaitlists(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("process-waitlists", flag.ContinueOnError)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	database.Connect(cfg.DatabaseURL, false)
+	defer database.DB.Close()
+
+	processed, err := registration.ProcessExpiredOffers(context.Background(), cfg)
+	fmt.Printf("Passed on expired waitlist offers for %d events\n", processed)
+	return err
+}
+
+func runSeed(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	domain := fs.String("domain", "demo.unibook.local", "email domain of the demo college")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	database.Connect(cfg.DatabaseURL, false)
+	defer database.DB.Close()
+
+	ctx := context.Background()
+	queries := db.New(database.DB)
+
+	if _, err := queries.GetCollegeByDomain(ctx, pgtype.Text{String: *domain, Valid: true}); err == nil {
+		fmt.Printf("A college with domain %s already exists, nothing to seed\n", *domain)
+		return nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	password, err := readPassword("Password for the demo accounts: ", true)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+	if err != nil {
+		return err
+	}
+
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := queries.WithTx(tx)
+
+	college, err := qtx.CreateCollege(ctx, db.CreateCollegeParams{
+		Name:       "Demo College",
+		DomainName: pgtype.Text{String: *domain, Valid: true},
+		HasPaid:    true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.CreateForum(ctx, db.CreateForumParams{
+		Name:        "Coding Club",
+		Description: pgtype.Text{String: "Demo forum created by unibook seed", Valid: true},
+		CollegeID:   college.ID,
+	}); err != nil {
+		return err
+	}
+
+	accounts := []struct {
+		name string
+		role db.UserRole
+	}{
+		{"Demo Admin", db.UserRoleCollegeAdmin},
+		{"Demo Teacher", db.UserRoleTeacher},
+		{"Demo Student", db.UserRoleStudent},
+	}
+	for _, account := range accounts {
+		user, err := qtx.CreateUser(ctx, db.CreateUserParams{
+			FullName:       account.name,
+			Email:          fmt.Sprintf("%s@%s", strings.ReplaceAll(string(account.role), "_", "."), *domain),
+			PasswordHash:   string(hashedPassword),
+			Role:           account.role,
+			CollegeID:      college.ID,
+			ApprovalStatus: db.ApprovalStatusApproved,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := qtx.VerifyUserEmail(ctx, user.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Created %-13s %s\n", account.role, user.Email)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	fmt.Printf("Seeded college %s (%s)\n", college.Name, college.ID)
+	return nil
+}
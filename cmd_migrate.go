@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"unibook-go/config"
+	"unibook-go/database"
+)
+
+const migrateUsage = `usage: unibook migrate <command>
+
+commands:
+  up                 apply all pending migrations
+  down [steps]       roll back the latest migration(s) (default 1)
+  redo               roll back and re-apply the latest migration
+  status             list migrations and whether they are applied
+  baseline <version> mark migrations up to version as applied without running them`
+
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return usageError{migrateUsage}
+	}
+
+	database.Connect(cfg.DatabaseURL, false)
+	defer database.DB.Close()
+
+	migrator, err := database.NewMigrator(database.DB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("nothing to apply")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return usageError{fmt.Sprintf("invalid number of steps: %q", args[1])}
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, m := range rolledBack {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+	case "redo":
+		m, err := migrator.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("redone   %04d_%s\n", m.Version, m.Name)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Modified {
+				state += " (modified since applied)"
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, state)
+		}
+
+	case "baseline":
+		if len(args) < 2 {
+			return usageError{"usage: unibook migrate baseline <version>"}
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return usageError{fmt.Sprintf("invalid version: %q", args[1])}
+		}
+		marked, err := migrator.Baseline(ctx, version)
+		for _, m := range marked {
+			fmt.Printf("marked   %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+	default:
+		return usageError{fmt.Sprintf("unknown migrate command %q\n\n%s", args[0], migrateUsage)}
+	}
+
+	return nil
+}
@@ -2,12 +2,48 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// RateLimit allows Max requests per Window for each key of a rate-limit bucket.
+type RateLimit struct {
+	Max    int
+	Window time.Duration
+}
+
+// DefaultRateLimits returns the limit of every rate-limit bucket. Each can be
+// overridden with RATE_LIMIT_<BUCKET>_MAX and RATE_LIMIT_<BUCKET>_WINDOW, where
+// <BUCKET> is the bucket name in upper case with dashes as underscores (e.g.
+// RATE_LIMIT_LOGIN_IP_MAX=50).
+func DefaultRateLimits() map[string]RateLimit {
+	return map[string]RateLimit{
+		"register-ip":            {Max: 10, Window: time.Hour},
+		"register-email":         {Max: 3, Window: time.Hour},
+		"verify-email-ip":        {Max: 30, Window: 15 * time.Minute},
+		"verify-email-email":     {Max: 10, Window: 15 * time.Minute},
+		"login-ip":               {Max: 30, Window: 15 * time.Minute},
+		"login-email":            {Max: 10, Window: 15 * time.Minute},
+		"resend-otp-ip":          {Max: 10, Window: time.Hour},
+		"resend-otp-email":       {Max: 5, Window: time.Hour},
+		"forgot-password-ip":     {Max: 10, Window: time.Hour},
+		"forgot-password-email":  {Max: 5, Window: time.Hour},
+		"verify-reset-otp-ip":    {Max: 30, Window: 15 * time.Minute},
+		"verify-reset-otp-email": {Max: 10, Window: 15 * time.Minute},
+		"reset-password-ip":      {Max: 30, Window: 15 * time.Minute},
+		"set-password-ip":        {Max: 30, Window: 15 * time.Minute},
+		"refresh-ip":             {Max: 60, Window: 15 * time.Minute},
+		"college-directory-ip":   {Max: 120, Window: time.Minute},
+		"calendar-feed-ip":       {Max: 60, Window: time.Minute},
+	}
+}
+
 type Config struct {
 	ServerAddr  string
 	DatabaseURL string
@@ -17,12 +53,66 @@ type Config struct {
 	SMTPPort    int
 	SMTPUser    string
 	SMTPPass    string
+
+	// AppURL is the base URL of the web app, used to build links in emails.
+	AppURL string
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	ResetTicketTTL  time.Duration
+	InviteTTL       time.Duration
+
+	// CollegeDirectoryCacheTTL is how long public college directory responses
+	// are cached, both in-process and by clients.
+	CollegeDirectoryCacheTTL time.Duration
+	// CollegeDirectoryCacheMaxBytes caps the in-process cache of directory
+	// responses; the oldest entries are evicted once it is full.
+	CollegeDirectoryCacheMaxBytes int
+
+	// WaitlistClaimWindow is how long a promoted waitlist entry holds a spot
+	// before it passes to the next person in line.
+	WaitlistClaimWindow time.Duration
+
+	OTPLength      int
+	OTPTTL         time.Duration
+	OTPMaxAttempts int
+	OTPLockout     time.Duration
+
+	// RateLimitStore is "memory" (default) or "postgres" for multi-instance deployments.
+	RateLimitStore string
+	// RateLimits holds the limit of every rate-limit bucket, by bucket name.
+	RateLimits map[string]RateLimit
+
+	// MigrateOnStartup applies pending embedded migrations when the server starts.
+	MigrateOnStartup bool
 }
 
-func LoadConfig() (*Config, error) {
+func loadEnvFile() {
 	if err := godotenv.Load(); err != nil {
 		fmt.Println("No .env file found, using environment variables")
 	}
+}
+
+// LoadDatabaseConfig loads only the database settings, for commands that do
+// nothing but query the database and so should not need SMTP or JWT settings.
+// OTPLockout is included because purging codes must keep locked ones around
+// until their lockout ends.
+func LoadDatabaseConfig() (*Config, error) {
+	loadEnvFile()
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		return nil, fmt.Errorf("DATABASE_URL must be set")
+	}
+	otpLockout, err := durationEnv("OTP_LOCKOUT", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{DatabaseURL: databaseURL, OTPLockout: otpLockout}, nil
+}
+
+func LoadConfig() (*Config, error) {
+	loadEnvFile()
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -33,6 +123,11 @@ func LoadConfig() (*Config, error) {
 		host = "localhost"
 	}
 
+	appURL := strings.TrimRight(os.Getenv("APP_URL"), "/")
+	if appURL == "" {
+		appURL = "http://localhost:3000"
+	}
+
 	smtpPortStr := os.Getenv("SMTP_PORT")
 	if smtpPortStr == "" {
 		return nil, fmt.Errorf("SMTP_PORT is not set in the environment")
@@ -42,6 +137,72 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
 	}
 
+	accessTokenTTL, err := durationEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	refreshTokenTTL, err := durationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	resetTicketTTL, err := durationEnv("RESET_TICKET_TTL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteTTL, err := durationEnv("INVITE_TTL", 72*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	collegeDirectoryCacheTTL, err := durationEnv("COLLEGE_DIRECTORY_CACHE_TTL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	collegeDirectoryCacheMaxBytes, err := intEnv("COLLEGE_DIRECTORY_CACHE_MAX_BYTES", 4<<20)
+	if err != nil {
+		return nil, err
+	}
+	if collegeDirectoryCacheMaxBytes < 1 {
+		return nil, fmt.Errorf("invalid COLLEGE_DIRECTORY_CACHE_MAX_BYTES: %d (must be positive)", collegeDirectoryCacheMaxBytes)
+	}
+
+	waitlistClaimWindow, err := durationEnv("WAITLIST_CLAIM_WINDOW", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	otpLength, err := intEnv("OTP_LENGTH", 6)
+	if err != nil {
+		return nil, err
+	}
+	otpTTL, err := durationEnv("OTP_TTL", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	otpMaxAttempts, err := intEnv("OTP_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+	otpLockout, err := durationEnv("OTP_LOCKOUT", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimitStore := os.Getenv("RATE_LIMIT_STORE")
+	if rateLimitStore == "" {
+		rateLimitStore = "memory"
+	}
+	if rateLimitStore != "memory" && rateLimitStore != "postgres" {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_STORE: %q (expected memory or postgres)", rateLimitStore)
+	}
+	rateLimits, err := rateLimitsEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		ServerAddr:  fmt.Sprintf("%s:%s", host, port),
 		DatabaseURL: os.Getenv("DATABASE_URL"),
@@ -51,11 +212,96 @@ func LoadConfig() (*Config, error) {
 		SMTPPort:    smtpPort,
 		SMTPUser:    os.Getenv("SMTP_USER"),
 		SMTPPass:    os.Getenv("SMTP_PASS"),
+
+		AppURL: appURL,
+
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+		ResetTicketTTL:  resetTicketTTL,
+		InviteTTL:       inviteTTL,
+
+		CollegeDirectoryCacheTTL:      collegeDirectoryCacheTTL,
+		CollegeDirectoryCacheMaxBytes: collegeDirectoryCacheMaxBytes,
+
+		WaitlistClaimWindow: waitlistClaimWindow,
+
+		OTPLength:      otpLength,
+		OTPTTL:         otpTTL,
+		OTPMaxAttempts: otpMaxAttempts,
+		OTPLockout:     otpLockout,
+
+		RateLimitStore: rateLimitStore,
+		RateLimits:     rateLimits,
+
+		MigrateOnStartup: os.Getenv("MIGRATE_ON_STARTUP") == "true",
 	}
 
 	if cfg.DatabaseURL == "" || cfg.JWTSecret == "" {
 		return nil, fmt.Errorf("DATABASE_URL and JWT_SECRET must be set")
 	}
 
+	if cfg.OTPLength < 4 || cfg.OTPLength > 10 {
+		return nil, fmt.Errorf("OTP_LENGTH must be between 4 and 10")
+	}
+	if cfg.OTPMaxAttempts < 1 {
+		return nil, fmt.Errorf("OTP_MAX_ATTEMPTS must be at least 1")
+	}
+
 	return cfg, nil
 }
+
+// durationEnv reads a Go duration string (e.g. "15m", "720h") from the
+// environment, falling back to def when the variable is unset.
+func durationEnv(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
+
+// rateLimitsEnv applies the RATE_LIMIT_<BUCKET>_MAX and _WINDOW overrides to
+// DefaultRateLimits.
+func rateLimitsEnv() (map[string]RateLimit, error) {
+	limits := DefaultRateLimits()
+	for _, bucket := range slices.Sorted(maps.Keys(limits)) {
+		prefix := "RATE_LIMIT_" + strings.ToUpper(strings.ReplaceAll(bucket, "-", "_"))
+		limit := limits[bucket]
+
+		max, err := intEnv(prefix+"_MAX", limit.Max)
+		if err != nil {
+			return nil, err
+		}
+		if max < 1 {
+			return nil, fmt.Errorf("%s_MAX must be at least 1", prefix)
+		}
+		window, err := durationEnv(prefix+"_WINDOW", limit.Window)
+		if err != nil {
+			return nil, err
+		}
+		if window <= 0 {
+			return nil, fmt.Errorf("%s_WINDOW must be positive", prefix)
+		}
+
+		limits[bucket] = RateLimit{Max: max, Window: window}
+	}
+	return limits, nil
+}
+
+// intEnv reads an integer from the environment, falling back to def when the
+// variable is unset.
+func intEnv(key string, def int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return n, nil
+}
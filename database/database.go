@@ -11,7 +11,9 @@ import (
 
 var DB *pgxpool.Pool
 
-func Connect(dbURL string) {
+// Connect opens the connection pool. When runMigrations is set, pending embedded
+// migrations are applied before it returns.
+func Connect(dbURL string, runMigrations bool) {
 	var err error
 
 	DB, err = pgxpool.New(context.Background(), dbURL)
@@ -25,4 +27,10 @@ func Connect(dbURL string) {
 	}
 
 	log.Println("Database connected successfully")
+
+	if runMigrations {
+		if err := Migrate(context.Background(), DB); err != nil {
+			log.Fatalf("Database migration failed: %v", err)
+		}
+	}
 }
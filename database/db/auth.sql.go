@@ -34,13 +34,41 @@ func (q *Queries) CreateForumHead(ctx context.Context, arg CreateForumHeadParams
 	return i, err
 }
 
+const createSuperAdmin = `-- name: CreateSuperAdmin :one
+INSERT INTO super_admins (
+  full_name, email, password_hash
+) VALUES (
+  $1, $2, $3
+)
+RETURNING id, full_name, email, password_hash, created_at
+`
+
+type CreateSuperAdminParams struct {
+	FullName     string `json:"full_name"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"password_hash"`
+}
+
+func (q *Queries) CreateSuperAdmin(ctx context.Context, arg CreateSuperAdminParams) (SuperAdmin, error) {
+	row := q.db.QueryRow(ctx, createSuperAdmin, arg.FullName, arg.Email, arg.PasswordHash)
+	var i SuperAdmin
+	err := row.Scan(
+		&i.ID,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   full_name, email, password_hash, role, college_id, approval_status
 ) VALUES (
   $1, $2, $3, $4, $5, $6
 )
-RETURNING id, full_name, email, password_hash, role, created_at, approval_status, is_email_verified, email_verification_token, email_verification_expires, password_reset_token, password_reset_expires, college_id
+RETURNING id, full_name, email, password_hash, role, created_at, approval_status, is_email_verified, college_id
 `
 
 type CreateUserParams struct {
@@ -72,17 +100,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.ApprovalStatus,
 		&i.IsEmailVerified,
-		&i.EmailVerificationToken,
-		&i.EmailVerificationExpires,
-		&i.PasswordResetToken,
-		&i.PasswordResetExpires,
 		&i.CollegeID,
 	)
 	return i, err
 }
 
 const getCollegeByID = `-- name: GetCollegeByID :one
-SELECT id, name, domain_name, has_paid, created_at, updated_at FROM colleges
+SELECT id, name, domain_name, has_paid, created_at, updated_at, allowed_email_domains, email_domain_override, onboarded_by, onboarded_at, require_staff_in_charge FROM colleges
 WHERE id = $1 LIMIT 1
 `
 
@@ -97,16 +121,21 @@ func (q *Queries) GetCollegeByID(ctx context.Context, id uuid.UUID) (College, er
 		&i.HasPaid,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AllowedEmailDomains,
+		&i.EmailDomainOverride,
+		&i.OnboardedBy,
+		&i.OnboardedAt,
+		&i.RequireStaffInCharge,
 	)
 	return i, err
 }
 
 const getSuperAdminByEmail = `-- name: GetSuperAdminByEmail :one
 SELECT id, full_name, email, password_hash, created_at FROM super_admins
-WHERE email = $1 LIMIT 1
+WHERE lower(email) = lower($1) LIMIT 1
 `
 
-// Fetches a super admin for login verification
+// Fetches a super admin for login verification (case-insensitive)
 func (q *Queries) GetSuperAdminByEmail(ctx context.Context, email string) (SuperAdmin, error) {
 	row := q.db.QueryRow(ctx, getSuperAdminByEmail, email)
 	var i SuperAdmin
@@ -138,11 +167,11 @@ func (q *Queries) GetSuperAdminByID(ctx context.Context, id uuid.UUID) (SuperAdm
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, full_name, email, password_hash, role, created_at, approval_status, is_email_verified, email_verification_token, email_verification_expires, password_reset_token, password_reset_expires, college_id FROM users
-WHERE email = $1 LIMIT 1
+SELECT id, full_name, email, password_hash, role, created_at, approval_status, is_email_verified, college_id FROM users
+WHERE lower(email) = lower($1) LIMIT 1
 `
 
-// Check if a user with a given email already exists
+// Check if a user with a given email already exists (case-insensitive, matches users_email_lower_unique)
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
 	row := q.db.QueryRow(ctx, getUserByEmail, email)
 	var i User
@@ -155,10 +184,6 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.CreatedAt,
 		&i.ApprovalStatus,
 		&i.IsEmailVerified,
-		&i.EmailVerificationToken,
-		&i.EmailVerificationExpires,
-		&i.PasswordResetToken,
-		&i.PasswordResetExpires,
 		&i.CollegeID,
 	)
 	return i, err
@@ -185,7 +210,7 @@ LEFT JOIN LATERAL (
             'name', "users_college"."name"
         )::json AS "data"
     FROM (
-        SELECT id, name, domain_name, has_paid, created_at, updated_at FROM "colleges" "users_college"
+        SELECT id, name, domain_name, has_paid, created_at, updated_at, allowed_email_domains, email_domain_override, onboarded_by, onboarded_at, require_staff_in_charge FROM "colleges" "users_college"
         WHERE "users_college"."id" = "users"."college_id"
         LIMIT 1
     ) "users_college"
@@ -254,52 +279,60 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow
 	return i, err
 }
 
-const setUserEmailVerificationDetails = `-- name: SetUserEmailVerificationDetails :exec
+const getUserPasswordHash = `-- name: GetUserPasswordHash :one
+SELECT password_hash FROM users
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserPasswordHash(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getUserPasswordHash, id)
+	var password_hash string
+	err := row.Scan(&password_hash)
+	return password_hash, err
+}
+
+const resetUserPassword = `-- name: ResetUserPassword :execrows
 UPDATE users
-SET 
-  email_verification_token = $2,
-  email_verification_expires = $3
-WHERE id = $1
+SET password_hash = $1
+WHERE id = $2 AND password_hash = $3
 `
 
-type SetUserEmailVerificationDetailsParams struct {
-	ID                       uuid.UUID        `json:"id"`
-	EmailVerificationToken   pgtype.Text      `json:"email_verification_token"`
-	EmailVerificationExpires pgtype.Timestamp `json:"email_verification_expires"`
+type ResetUserPasswordParams struct {
+	NewHash string    `json:"new_hash"`
+	ID      uuid.UUID `json:"id"`
+	OldHash string    `json:"old_hash"`
 }
 
-// Sets the OTP token and expiration for a user after registration
-func (q *Queries) SetUserEmailVerificationDetails(ctx context.Context, arg SetUserEmailVerificationDetailsParams) error {
-	_, err := q.db.Exec(ctx, setUserEmailVerificationDetails, arg.ID, arg.EmailVerificationToken, arg.EmailVerificationExpires)
-	return err
+// Sets a new password only if the old hash still matches, which makes reset tickets single-use
+func (q *Queries) ResetUserPassword(ctx context.Context, arg ResetUserPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, resetUserPassword, arg.NewHash, arg.ID, arg.OldHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const setUserPasswordResetDetails = `-- name: SetUserPasswordResetDetails :exec
-UPDATE users
+const updateSuperAdminPassword = `-- name: UpdateSuperAdminPassword :exec
+UPDATE super_admins
 SET
-  password_reset_token=$2,
-  password_reset_expires=$3
+  password_hash=$2
 WHERE id=$1
 `
 
-type SetUserPasswordResetDetailsParams struct {
-	ID                   uuid.UUID        `json:"id"`
-	PasswordResetToken   pgtype.Text      `json:"password_reset_token"`
-	PasswordResetExpires pgtype.Timestamp `json:"password_reset_expires"`
+type UpdateSuperAdminPasswordParams struct {
+	ID           uuid.UUID `json:"id"`
+	PasswordHash string    `json:"password_hash"`
 }
 
-// Sets the user password reset token
-func (q *Queries) SetUserPasswordResetDetails(ctx context.Context, arg SetUserPasswordResetDetailsParams) error {
-	_, err := q.db.Exec(ctx, setUserPasswordResetDetails, arg.ID, arg.PasswordResetToken, arg.PasswordResetExpires)
+func (q *Queries) UpdateSuperAdminPassword(ctx context.Context, arg UpdateSuperAdminPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateSuperAdminPassword, arg.ID, arg.PasswordHash)
 	return err
 }
 
 const updateUserPassword = `-- name: UpdateUserPassword :exec
 UPDATE users
 SET
-  password_hash=$2,
-  password_reset_expires=NULL,
-  password_reset_token=NULL
+  password_hash=$2
 WHERE id=$1
 `
 
@@ -317,14 +350,12 @@ func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPassword
 const verifyUserEmail = `-- name: VerifyUserEmail :one
 UPDATE users
 SET
-  is_email_verified = true,
-  email_verification_token = NULL,
-  email_verification_expires = NULL
+  is_email_verified = true
 WHERE id = $1
-RETURNING id, full_name, email, password_hash, role, created_at, approval_status, is_email_verified, email_verification_token, email_verification_expires, password_reset_token, password_reset_expires, college_id
+RETURNING id, full_name, email, password_hash, role, created_at, approval_status, is_email_verified, college_id
 `
 
-// Marks a user's email as verified and clears the token fields
+// Marks a user's email as verified
 func (q *Queries) VerifyUserEmail(ctx context.Context, id uuid.UUID) (User, error) {
 	row := q.db.QueryRow(ctx, verifyUserEmail, id)
 	var i User
@@ -337,10 +368,6 @@ func (q *Queries) VerifyUserEmail(ctx context.Context, id uuid.UUID) (User, erro
 		&i.CreatedAt,
 		&i.ApprovalStatus,
 		&i.IsEmailVerified,
-		&i.EmailVerificationToken,
-		&i.EmailVerificationExpires,
-		&i.PasswordResetToken,
-		&i.PasswordResetExpires,
 		&i.CollegeID,
 	)
 	return i, err
@@ -142,6 +142,50 @@ func (ns NullEventStatus) Value() (driver.Value, error) {
 	return string(ns.EventStatus), nil
 }
 
+type OtpPurpose string
+
+const (
+	OtpPurposeEmailVerification OtpPurpose = "email_verification"
+	OtpPurposePasswordReset     OtpPurpose = "password_reset"
+	OtpPurposeEmailChange       OtpPurpose = "email_change"
+	OtpPurposeLogin             OtpPurpose = "login"
+)
+
+func (e *OtpPurpose) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OtpPurpose(s)
+	case string:
+		*e = OtpPurpose(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OtpPurpose: %T", src)
+	}
+	return nil
+}
+
+type NullOtpPurpose struct {
+	OtpPurpose OtpPurpose `json:"otp_purpose"`
+	Valid      bool       `json:"valid"` // Valid is true if OtpPurpose is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOtpPurpose) Scan(value interface{}) error {
+	if value == nil {
+		ns.OtpPurpose, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OtpPurpose.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOtpPurpose) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OtpPurpose), nil
+}
+
 type UserRole string
 
 const (
@@ -186,31 +230,44 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
+type CalendarFeedToken struct {
+	UserID    uuid.UUID        `json:"user_id"`
+	TokenHash string           `json:"token_hash"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
 type College struct {
-	ID         uuid.UUID        `json:"id"`
-	Name       string           `json:"name"`
-	DomainName pgtype.Text      `json:"domain_name"`
-	HasPaid    bool             `json:"has_paid"`
-	CreatedAt  pgtype.Timestamp `json:"created_at"`
-	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+	ID                   uuid.UUID        `json:"id"`
+	Name                 string           `json:"name"`
+	DomainName           pgtype.Text      `json:"domain_name"`
+	HasPaid              bool             `json:"has_paid"`
+	CreatedAt            pgtype.Timestamp `json:"created_at"`
+	UpdatedAt            pgtype.Timestamp `json:"updated_at"`
+	AllowedEmailDomains  []string         `json:"allowed_email_domains"`
+	EmailDomainOverride  bool             `json:"email_domain_override"`
+	OnboardedBy          pgtype.UUID      `json:"onboarded_by"`
+	OnboardedAt          pgtype.Timestamp `json:"onboarded_at"`
+	RequireStaffInCharge bool             `json:"require_staff_in_charge"`
 }
 
 type Event struct {
-	ID               uuid.UUID        `json:"id"`
-	Name             string           `json:"name"`
-	Description      pgtype.Text      `json:"description"`
-	StartTime        pgtype.Timestamp `json:"start_time"`
-	EndTime          pgtype.Timestamp `json:"end_time"`
-	Status           EventStatus      `json:"status"`
-	CreatedAt        pgtype.Timestamp `json:"created_at"`
-	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
-	BannerImage      pgtype.Text      `json:"banner_image"`
-	ResizeMode       pgtype.Text      `json:"resize_mode"`
-	RegistrationLink pgtype.Text      `json:"registration_link"`
-	CollegeID        uuid.UUID        `json:"college_id"`
-	VenueID          pgtype.UUID      `json:"venue_id"`
-	OrganizerID      uuid.UUID        `json:"organizer_id"`
-	ForumID          uuid.UUID        `json:"forum_id"`
+	ID                uuid.UUID        `json:"id"`
+	Name              string           `json:"name"`
+	Description       pgtype.Text      `json:"description"`
+	StartTime         pgtype.Timestamp `json:"start_time"`
+	EndTime           pgtype.Timestamp `json:"end_time"`
+	Status            EventStatus      `json:"status"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+	UpdatedAt         pgtype.Timestamp `json:"updated_at"`
+	BannerImage       pgtype.Text      `json:"banner_image"`
+	ResizeMode        pgtype.Text      `json:"resize_mode"`
+	RegistrationLink  pgtype.Text      `json:"registration_link"`
+	CollegeID         uuid.UUID        `json:"college_id"`
+	VenueID           pgtype.UUID      `json:"venue_id"`
+	OrganizerID       uuid.UUID        `json:"organizer_id"`
+	ForumID           uuid.UUID        `json:"forum_id"`
+	Sequence          int32            `json:"sequence"`
+	RegistrationLimit pgtype.Int4      `json:"registration_limit"`
 }
 
 type EventCollaborator struct {
@@ -221,6 +278,15 @@ type EventCollaborator struct {
 	CreatedAt            pgtype.Timestamp    `json:"created_at"`
 }
 
+type EventRegistration struct {
+	ID          uuid.UUID        `json:"id"`
+	EventID     uuid.UUID        `json:"event_id"`
+	UserID      uuid.UUID        `json:"user_id"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	CheckedInAt pgtype.Timestamp `json:"checked_in_at"`
+	CheckedInBy pgtype.UUID      `json:"checked_in_by"`
+}
+
 type EventStaffAssignment struct {
 	ID             uuid.UUID          `json:"id"`
 	AssignmentRole pgtype.Text        `json:"assignment_role"`
@@ -230,12 +296,32 @@ type EventStaffAssignment struct {
 	CreatedAt      pgtype.Timestamp   `json:"created_at"`
 }
 
+type EventStatusTransition struct {
+	ID         uuid.UUID        `json:"id"`
+	EventID    uuid.UUID        `json:"event_id"`
+	FromStatus NullEventStatus  `json:"from_status"`
+	ToStatus   EventStatus      `json:"to_status"`
+	ActorID    pgtype.UUID      `json:"actor_id"`
+	Note       pgtype.Text      `json:"note"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type EventWaitlistEntry struct {
+	ID             uuid.UUID        `json:"id"`
+	EventID        uuid.UUID        `json:"event_id"`
+	UserID         uuid.UUID        `json:"user_id"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	OfferedAt      pgtype.Timestamp `json:"offered_at"`
+	OfferExpiresAt pgtype.Timestamp `json:"offer_expires_at"`
+}
+
 type Forum struct {
 	ID          uuid.UUID        `json:"id"`
 	Name        string           `json:"name"`
 	Description pgtype.Text      `json:"description"`
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
 	CollegeID   uuid.UUID        `json:"college_id"`
+	ArchivedAt  pgtype.Timestamp `json:"archived_at"`
 }
 
 type ForumHead struct {
@@ -244,6 +330,38 @@ type ForumHead struct {
 	IsVerified bool      `json:"is_verified"`
 }
 
+type Otp struct {
+	ID          uuid.UUID        `json:"id"`
+	UserID      uuid.UUID        `json:"user_id"`
+	Purpose     OtpPurpose       `json:"purpose"`
+	CodeHash    string           `json:"code_hash"`
+	Attempts    int32            `json:"attempts"`
+	MaxAttempts int32            `json:"max_attempts"`
+	ExpiresAt   pgtype.Timestamp `json:"expires_at"`
+	LockedAt    pgtype.Timestamp `json:"locked_at"`
+	ConsumedAt  pgtype.Timestamp `json:"consumed_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+type RateLimitBucket struct {
+	Key     string             `json:"key"`
+	Hits    int32              `json:"hits"`
+	ResetAt pgtype.Timestamptz `json:"reset_at"`
+}
+
+type Session struct {
+	ID               uuid.UUID        `json:"id"`
+	UserID           uuid.UUID        `json:"user_id"`
+	IsSuperAdmin     bool             `json:"is_super_admin"`
+	RefreshTokenHash string           `json:"refresh_token_hash"`
+	UserAgent        pgtype.Text      `json:"user_agent"`
+	IpAddress        pgtype.Text      `json:"ip_address"`
+	ExpiresAt        pgtype.Timestamp `json:"expires_at"`
+	RevokedAt        pgtype.Timestamp `json:"revoked_at"`
+	LastUsedAt       pgtype.Timestamp `json:"last_used_at"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+}
+
 type SuperAdmin struct {
 	ID           uuid.UUID        `json:"id"`
 	FullName     string           `json:"full_name"`
@@ -253,19 +371,21 @@ type SuperAdmin struct {
 }
 
 type User struct {
-	ID                       uuid.UUID        `json:"id"`
-	FullName                 string           `json:"full_name"`
-	Email                    string           `json:"email"`
-	PasswordHash             string           `json:"password_hash"`
-	Role                     UserRole         `json:"role"`
-	CreatedAt                pgtype.Timestamp `json:"created_at"`
-	ApprovalStatus           ApprovalStatus   `json:"approval_status"`
-	IsEmailVerified          bool             `json:"is_email_verified"`
-	EmailVerificationToken   pgtype.Text      `json:"email_verification_token"`
-	EmailVerificationExpires pgtype.Timestamp `json:"email_verification_expires"`
-	PasswordResetToken       pgtype.Text      `json:"password_reset_token"`
-	PasswordResetExpires     pgtype.Timestamp `json:"password_reset_expires"`
-	CollegeID                uuid.UUID        `json:"college_id"`
+	ID              uuid.UUID        `json:"id"`
+	FullName        string           `json:"full_name"`
+	Email           string           `json:"email"`
+	PasswordHash    string           `json:"password_hash"`
+	Role            UserRole         `json:"role"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	ApprovalStatus  ApprovalStatus   `json:"approval_status"`
+	IsEmailVerified bool             `json:"is_email_verified"`
+	CollegeID       uuid.UUID        `json:"college_id"`
+}
+
+type UserInvitation struct {
+	UserID    uuid.UUID        `json:"user_id"`
+	InvitedBy pgtype.UUID      `json:"invited_by"`
+	SentAt    pgtype.Timestamp `json:"sent_at"`
 }
 
 type Venue struct {
@@ -0,0 +1,381 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// statementBreakpoint separates statements in the drizzle-style migration files.
+const statementBreakpoint = "--> statement-breakpoint"
+
+// migrationLockID is the pg_advisory_lock key held while migrating, so that two
+// instances starting at once do not apply the same migration twice.
+const migrationLockID = 7261394012
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+?)(\.down)?\.sql$`)
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       []string
+	Down     []string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when an applied migration's file no longer matches the
+	// checksum recorded when it was applied.
+	Modified bool
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations embedded in the binary.
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := LoadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// LoadMigrations reads NNNN_name.sql files (and optional NNNN_name.down.sql
+// counterparts) from dir, ordered by version. Versions must be unique and
+// consecutive.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type migrationFile struct {
+		version int
+		down    bool
+	}
+	byVersion := map[int]*Migration{}
+	seen := map[migrationFile]bool{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %04d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+
+		// 1_x.sql and 0001_x.sql are different files with the same version.
+		file := migrationFile{version: version, down: match[3] == ".down"}
+		if seen[file] {
+			return nil, fmt.Errorf("migration %04d_%s is defined more than once", version, m.Name)
+		}
+		seen[file] = true
+
+		if file.down {
+			m.Down = splitStatements(string(content))
+		} else {
+			m.Up = splitStatements(string(content))
+			sum := sha256.Sum256(content)
+			m.Checksum = hex.EncodeToString(sum[:])
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Checksum == "" {
+			return nil, fmt.Errorf("migration %04d_%s has a down file but no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i := 1; i < len(migrations); i++ {
+		if want := migrations[i-1].Version + 1; migrations[i].Version != want {
+			return nil, fmt.Errorf("migration %04d is missing before %04d_%s", want, migrations[i].Version, migrations[i].Name)
+		}
+	}
+	return migrations, nil
+}
+
+func splitStatements(sql string) []string {
+	var statements []string
+	for _, part := range strings.Split(sql, statementBreakpoint) {
+		if stmt := strings.TrimSpace(part); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	return statements
+}
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS "schema_migrations" (
+	"version" integer PRIMARY KEY NOT NULL,
+	"name" text NOT NULL,
+	"checksum" text NOT NULL,
+	"applied_at" timestamp with time zone DEFAULT now() NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.Query(ctx, `SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]appliedMigration{}
+	for rows.Next() {
+		var version int
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) run(ctx context.Context, conn *pgxpool.Conn, migration Migration, up bool) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	statements := migration.Up
+	if !up {
+		statements = migration.Down
+	}
+	for i, stmt := range statements {
+		if _, err := tx.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("migration %04d_%s statement %d: %w", migration.Version, migration.Name, i+1, err)
+		}
+	}
+
+	if up {
+		_, err = tx.Exec(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+			migration.Version, migration.Name, migration.Checksum)
+	} else {
+		_, err = tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Up applies all pending migrations in order and returns the ones it applied.
+// It refuses to run if an already applied migration has been edited.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if a, ok := applied[migration.Version]; ok {
+				if a.checksum != migration.Checksum {
+					return fmt.Errorf("migration %04d_%s was modified after it was applied", migration.Version, migration.Name)
+				}
+				continue
+			}
+			if err := m.run(ctx, conn, migration, true); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the latest steps applied migrations and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if len(migration.Down) == 0 {
+				return fmt.Errorf("migration %04d_%s has no down migration", migration.Version, migration.Name)
+			}
+			if err := m.run(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	var redone *Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if len(migration.Down) == 0 {
+				return fmt.Errorf("migration %04d_%s has no down migration", migration.Version, migration.Name)
+			}
+			if err := m.run(ctx, conn, migration, false); err != nil {
+				return err
+			}
+			if err := m.run(ctx, conn, migration, true); err != nil {
+				return err
+			}
+			redone = &migration
+			return nil
+		}
+		return errors.New("no applied migrations to redo")
+	})
+	return redone, err
+}
+
+// Baseline records every migration up to and including version as applied
+// without running it. It is meant for databases whose schema was created before
+// the runner existed (e.g. by drizzle-kit).
+func (m *Migrator) Baseline(ctx context.Context, version int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			_, err := conn.Exec(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+				migration.Version, migration.Name, migration.Checksum)
+			if err != nil {
+				return err
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Migration: migration}
+			if a, ok := applied[migration.Version]; ok {
+				status.Applied = true
+				status.AppliedAt = a.appliedAt
+				status.Modified = a.checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Migrate applies pending migrations and logs what was applied.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	migrator, err := NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		log.Println("Database schema is up to date")
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{"single", "CREATE TABLE a ();", []string{"CREATE TABLE a ();"}},
+		{"empty", "", nil},
+		{"only whitespace", " \n\t", nil},
+		{
+			"breakpoints",
+			"CREATE TABLE a ();--> statement-breakpoint\nCREATE TABLE b ();",
+			[]string{"CREATE TABLE a ();", "CREATE TABLE b ();"},
+		},
+		{
+			"trailing breakpoint",
+			"CREATE TABLE a ();--> statement-breakpoint\n",
+			[]string{"CREATE TABLE a ();"},
+		},
+		{
+			"blank statements",
+			"--> statement-breakpoint\nCREATE TABLE a ();\n--> statement-breakpoint\n\n--> statement-breakpoint\nCREATE TABLE b ();",
+			[]string{"CREATE TABLE a ();", "CREATE TABLE b ();"},
+		},
+		{
+			"semicolons inside a statement are kept",
+			"DO $$ BEGIN PERFORM 1; PERFORM 2; END $$;--> statement-breakpoint\nSELECT 1;",
+			[]string{"DO $$ BEGIN PERFORM 1; PERFORM 2; END $$;", "SELECT 1;"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.sql); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements(%q) = %q, want %q", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(sql string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(sql)} }
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []Migration
+		wantErr string
+	}{
+		{
+			name: "pairs up and down files by version",
+			files: fstest.MapFS{
+				"migrations/0001_posts.sql":       file("CREATE TABLE posts ();"),
+				"migrations/0000_init.sql":        file("CREATE TABLE a ();--> statement-breakpoint\nCREATE TABLE b ();"),
+				"migrations/0000_init.down.sql":   file("DROP TABLE b;--> statement-breakpoint\nDROP TABLE a;"),
+				"migrations/0001_posts.down.sql":  file("DROP TABLE posts;"),
+				"migrations/README.md":            file("not a migration"),
+				"migrations/0002_Bad-Name.sql":    file("ignored"),
+				"migrations/nested/0003_deep.sql": file("ignored"),
+			},
+			want: []Migration{
+				{Version: 0, Name: "init", Up: []string{"CREATE TABLE a ();", "CREATE TABLE b ();"}, Down: []string{"DROP TABLE b;", "DROP TABLE a;"}},
+				{Version: 1, Name: "posts", Up: []string{"CREATE TABLE posts ();"}, Down: []string{"DROP TABLE posts;"}},
+			},
+		},
+		{
+			name: "down file is optional",
+			files: fstest.MapFS{
+				"migrations/0000_init.sql": file("CREATE TABLE a ();"),
+			},
+			want: []Migration{
+				{Version: 0, Name: "init", Up: []string{"CREATE TABLE a ();"}},
+			},
+		},
+		{
+			name: "down file without up file",
+			files: fstest.MapFS{
+				"migrations/0000_init.sql":       file("CREATE TABLE a ();"),
+				"migrations/0001_posts.down.sql": file("DROP TABLE posts;"),
+			},
+			wantErr: "has a down file but no up file",
+		},
+		{
+			name: "missing version",
+			files: fstest.MapFS{
+				"migrations/0000_init.sql":  file("CREATE TABLE a ();"),
+				"migrations/0002_posts.sql": file("CREATE TABLE posts ();"),
+			},
+			wantErr: "migration 0001 is missing",
+		},
+		{
+			name: "duplicate version with different names",
+			files: fstest.MapFS{
+				"migrations/0000_init.sql":  file("CREATE TABLE a ();"),
+				"migrations/0001_posts.sql": file("CREATE TABLE posts ();"),
+				"migrations/0001_users.sql": file("CREATE TABLE users ();"),
+			},
+			wantErr: "conflicting names",
+		},
+		{
+			name: "duplicate version with different padding",
+			files: fstest.MapFS{
+				"migrations/0000_init.sql": file("CREATE TABLE a ();"),
+				"migrations/0_init.sql":    file("CREATE TABLE b ();"),
+			},
+			wantErr: "defined more than once",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadMigrations(tt.files, "migrations")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadMigrations() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadMigrations() error = %v", err)
+			}
+
+			for i := range got {
+				if got[i].Checksum == "" {
+					t.Errorf("migration %04d has no checksum", got[i].Version)
+				}
+				got[i].Checksum = ""
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadMigrations() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrationChecksum(t *testing.T) {
+	checksum := func(t *testing.T, files fstest.MapFS) string {
+		t.Helper()
+		migrations, err := LoadMigrations(files, "migrations")
+		if err != nil {
+			t.Fatalf("LoadMigrations() error = %v", err)
+		}
+		return migrations[0].Checksum
+	}
+
+	base := checksum(t, fstest.MapFS{
+		"migrations/0000_init.sql": {Data: []byte("CREATE TABLE a ();")},
+	})
+
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		same  bool
+	}{
+		{
+			name:  "same content",
+			files: fstest.MapFS{"migrations/0000_init.sql": {Data: []byte("CREATE TABLE a ();")}},
+			same:  true,
+		},
+		{
+			name: "down file and later migrations do not count",
+			files: fstest.MapFS{
+				"migrations/0000_init.sql":      {Data: []byte("CREATE TABLE a ();")},
+				"migrations/0000_init.down.sql": {Data: []byte("DROP TABLE a;")},
+				"migrations/0001_posts.sql":     {Data: []byte("CREATE TABLE posts ();")},
+			},
+			same: true,
+		},
+		{
+			name:  "edited statement",
+			files: fstest.MapFS{"migrations/0000_init.sql": {Data: []byte("CREATE TABLE b ();")}},
+		},
+		{
+			name:  "whitespace change",
+			files: fstest.MapFS{"migrations/0000_init.sql": {Data: []byte("CREATE TABLE a ();\n")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checksum(t, tt.files); (got == base) != tt.same {
+				t.Errorf("checksum = %s, base = %s, want same = %v", got, base, tt.same)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := LoadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 0 {
+		t.Fatalf("embedded migrations should start at 0000, got %d migrations", len(migrations))
+	}
+	for _, migration := range migrations {
+		if len(migration.Up) == 0 {
+			t.Errorf("migration %04d_%s has no statements", migration.Version, migration.Name)
+		}
+		if len(migration.Down) == 0 {
+			t.Errorf("migration %04d_%s has no down migration", migration.Version, migration.Name)
+		}
+	}
+}
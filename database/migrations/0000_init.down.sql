@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS "event_collaborators" CASCADE;--> statement-breakpoint
+DROP TABLE IF EXISTS "event_staff_assignments" CASCADE;--> statement-breakpoint
+DROP TABLE IF EXISTS "events" CASCADE;--> statement-breakpoint
+DROP TABLE IF EXISTS "forum_heads" CASCADE;--> statement-breakpoint
+DROP TABLE IF EXISTS "forums" CASCADE;--> statement-breakpoint
+DROP TABLE IF EXISTS "super_admins" CASCADE;--> statement-breakpoint
+DROP TABLE IF EXISTS "users" CASCADE;--> statement-breakpoint
+DROP TABLE IF EXISTS "venues" CASCADE;--> statement-breakpoint
+DROP TABLE IF EXISTS "colleges" CASCADE;--> statement-breakpoint
+DROP TYPE IF EXISTS "public"."user_role";--> statement-breakpoint
+DROP TYPE IF EXISTS "public"."event_status";--> statement-breakpoint
+DROP TYPE IF EXISTS "public"."collaboration_status";--> statement-breakpoint
+DROP TYPE IF EXISTS "public"."approval_status";
@@ -0,0 +1,14 @@
+CREATE TABLE "sessions" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"user_id" uuid NOT NULL,
+	"is_super_admin" boolean DEFAULT false NOT NULL,
+	"refresh_token_hash" text NOT NULL,
+	"user_agent" text,
+	"ip_address" text,
+	"expires_at" timestamp NOT NULL,
+	"revoked_at" timestamp,
+	"last_used_at" timestamp DEFAULT now() NOT NULL,
+	"created_at" timestamp DEFAULT now() NOT NULL
+);
+--> statement-breakpoint
+CREATE INDEX "sessions_user_id_idx" ON "sessions" USING btree ("user_id");
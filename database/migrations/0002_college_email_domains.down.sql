@@ -0,0 +1,2 @@
+ALTER TABLE "colleges" DROP COLUMN "email_domain_override";--> statement-breakpoint
+ALTER TABLE "colleges" DROP COLUMN "allowed_email_domains";
@@ -0,0 +1,2 @@
+ALTER TABLE "colleges" ADD COLUMN "allowed_email_domains" text[] DEFAULT '{}' NOT NULL;--> statement-breakpoint
+ALTER TABLE "colleges" ADD COLUMN "email_domain_override" boolean DEFAULT false NOT NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE "users" ADD COLUMN "email_verification_token" text;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "email_verification_expires" timestamp;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "password_reset_token" text;--> statement-breakpoint
+ALTER TABLE "users" ADD COLUMN "password_reset_expires" timestamp;--> statement-breakpoint
+DROP TABLE IF EXISTS "otps";--> statement-breakpoint
+DROP TYPE IF EXISTS "public"."otp_purpose";
@@ -0,0 +1,20 @@
+CREATE TYPE "public"."otp_purpose" AS ENUM('email_verification', 'password_reset', 'email_change', 'login');--> statement-breakpoint
+CREATE TABLE "otps" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"user_id" uuid NOT NULL,
+	"purpose" "otp_purpose" NOT NULL,
+	"code_hash" text NOT NULL,
+	"attempts" integer DEFAULT 0 NOT NULL,
+	"max_attempts" integer NOT NULL,
+	"expires_at" timestamp NOT NULL,
+	"locked_at" timestamp,
+	"consumed_at" timestamp,
+	"created_at" timestamp DEFAULT now() NOT NULL
+);
+--> statement-breakpoint
+ALTER TABLE "otps" ADD CONSTRAINT "otps_user_id_users_id_fk" FOREIGN KEY ("user_id") REFERENCES "public"."users"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+CREATE INDEX "otps_user_id_purpose_idx" ON "otps" USING btree ("user_id","purpose");--> statement-breakpoint
+ALTER TABLE "users" DROP COLUMN "email_verification_token";--> statement-breakpoint
+ALTER TABLE "users" DROP COLUMN "email_verification_expires";--> statement-breakpoint
+ALTER TABLE "users" DROP COLUMN "password_reset_token";--> statement-breakpoint
+ALTER TABLE "users" DROP COLUMN "password_reset_expires";
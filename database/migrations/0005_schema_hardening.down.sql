@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS "venues_college_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "users_college_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "forums_college_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "forum_heads_forum_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "events_forum_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "events_organizer_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "events_venue_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "events_college_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "event_staff_assignments_user_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "event_staff_assignments_event_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "event_collaborators_collaborating_forum_id_idx";--> statement-breakpoint
+DROP INDEX IF EXISTS "event_collaborators_event_id_idx";--> statement-breakpoint
+ALTER TABLE "venues" DROP CONSTRAINT IF EXISTS "venues_capacity_positive";--> statement-breakpoint
+ALTER TABLE "events" DROP CONSTRAINT IF EXISTS "events_end_time_after_start_time";--> statement-breakpoint
+DROP INDEX IF EXISTS "users_email_lower_unique";--> statement-breakpoint
+ALTER TABLE "forum_heads" DROP CONSTRAINT IF EXISTS "forum_heads_user_id_forum_id_pk";
@@ -0,0 +1,29 @@
+DELETE FROM "forum_heads" "a" USING "forum_heads" "b"
+WHERE "a"."user_id" = "b"."user_id" AND "a"."forum_id" = "b"."forum_id"
+	AND ("b"."is_verified" AND NOT "a"."is_verified" OR "a"."is_verified" = "b"."is_verified" AND "a"."ctid" < "b"."ctid");--> statement-breakpoint
+ALTER TABLE "forum_heads" ADD CONSTRAINT "forum_heads_user_id_forum_id_pk" PRIMARY KEY("user_id","forum_id");--> statement-breakpoint
+DO $$
+DECLARE
+	"duplicates" text;
+BEGIN
+	SELECT string_agg("email", ', ' ORDER BY "email") INTO "duplicates"
+	FROM (SELECT lower("email") AS "email" FROM "users" GROUP BY lower("email") HAVING count(*) > 1) AS "d";
+	IF "duplicates" IS NOT NULL THEN
+		RAISE EXCEPTION 'Several users share these email addresses (ignoring case): %. Merge or remove the duplicate accounts, then run the migration again.', "duplicates";
+	END IF;
+END $$;--> statement-breakpoint
+CREATE UNIQUE INDEX "users_email_lower_unique" ON "users" USING btree (lower("email"));--> statement-breakpoint
+ALTER TABLE "events" ADD CONSTRAINT "events_end_time_after_start_time" CHECK ("events"."end_time" > "events"."start_time");--> statement-breakpoint
+ALTER TABLE "venues" ADD CONSTRAINT "venues_capacity_positive" CHECK ("venues"."capacity" > 0);--> statement-breakpoint
+CREATE INDEX "event_collaborators_event_id_idx" ON "event_collaborators" USING btree ("event_id");--> statement-breakpoint
+CREATE INDEX "event_collaborators_collaborating_forum_id_idx" ON "event_collaborators" USING btree ("collaborating_forum_id");--> statement-breakpoint
+CREATE INDEX "event_staff_assignments_event_id_idx" ON "event_staff_assignments" USING btree ("event_id");--> statement-breakpoint
+CREATE INDEX "event_staff_assignments_user_id_idx" ON "event_staff_assignments" USING btree ("user_id");--> statement-breakpoint
+CREATE INDEX "events_college_id_idx" ON "events" USING btree ("college_id");--> statement-breakpoint
+CREATE INDEX "events_venue_id_idx" ON "events" USING btree ("venue_id");--> statement-breakpoint
+CREATE INDEX "events_organizer_id_idx" ON "events" USING btree ("organizer_id");--> statement-breakpoint
+CREATE INDEX "events_forum_id_idx" ON "events" USING btree ("forum_id");--> statement-breakpoint
+CREATE INDEX "forum_heads_forum_id_idx" ON "forum_heads" USING btree ("forum_id");--> statement-breakpoint
+CREATE INDEX "forums_college_id_idx" ON "forums" USING btree ("college_id");--> statement-breakpoint
+CREATE INDEX "users_college_id_idx" ON "users" USING btree ("college_id");--> statement-breakpoint
+CREATE INDEX "venues_college_id_idx" ON "venues" USING btree ("college_id");
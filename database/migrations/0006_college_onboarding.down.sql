@@ -0,0 +1,3 @@
+ALTER TABLE "colleges" DROP CONSTRAINT IF EXISTS "colleges_onboarded_by_super_admins_id_fk";--> statement-breakpoint
+ALTER TABLE "colleges" DROP COLUMN "onboarded_at";--> statement-breakpoint
+ALTER TABLE "colleges" DROP COLUMN "onboarded_by";
@@ -0,0 +1,3 @@
+ALTER TABLE "colleges" ADD COLUMN "onboarded_by" uuid;--> statement-breakpoint
+ALTER TABLE "colleges" ADD COLUMN "onboarded_at" timestamp;--> statement-breakpoint
+ALTER TABLE "colleges" ADD CONSTRAINT "colleges_onboarded_by_super_admins_id_fk" FOREIGN KEY ("onboarded_by") REFERENCES "public"."super_admins"("id") ON DELETE set null ON UPDATE no action;
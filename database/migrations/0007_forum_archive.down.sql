@@ -0,0 +1 @@
+ALTER TABLE "forums" DROP COLUMN "archived_at";
@@ -0,0 +1 @@
+ALTER TABLE "forums" ADD COLUMN "archived_at" timestamp;
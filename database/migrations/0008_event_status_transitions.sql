@@ -0,0 +1,13 @@
+CREATE TABLE "event_status_transitions" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"event_id" uuid NOT NULL,
+	"from_status" "event_status",
+	"to_status" "event_status" NOT NULL,
+	"actor_id" uuid,
+	"note" text,
+	"created_at" timestamp DEFAULT now() NOT NULL
+);
+--> statement-breakpoint
+ALTER TABLE "event_status_transitions" ADD CONSTRAINT "event_status_transitions_event_id_events_id_fk" FOREIGN KEY ("event_id") REFERENCES "public"."events"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "event_status_transitions" ADD CONSTRAINT "event_status_transitions_actor_id_users_id_fk" FOREIGN KEY ("actor_id") REFERENCES "public"."users"("id") ON DELETE set null ON UPDATE no action;--> statement-breakpoint
+CREATE INDEX "event_status_transitions_event_id_idx" ON "event_status_transitions" USING btree ("event_id");
@@ -0,0 +1 @@
+ALTER TABLE "events" DROP CONSTRAINT IF EXISTS "events_venue_no_overlap";
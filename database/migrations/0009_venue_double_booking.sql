@@ -0,0 +1,15 @@
+CREATE EXTENSION IF NOT EXISTS "btree_gist";--> statement-breakpoint
+DO $$
+DECLARE
+	"overlaps" text;
+BEGIN
+	SELECT string_agg(format('%s and %s', "a"."id", "b"."id"), ', ' ORDER BY "a"."id", "b"."id") INTO "overlaps"
+	FROM "events" "a"
+	JOIN "events" "b" ON "a"."id" < "b"."id" AND "a"."venue_id" = "b"."venue_id"
+	WHERE "a"."status" <> 'cancelled' AND "b"."status" <> 'cancelled'
+		AND tsrange("a"."start_time", "a"."end_time", '[)') && tsrange("b"."start_time", "b"."end_time", '[)');
+	IF "overlaps" IS NOT NULL THEN
+		RAISE EXCEPTION 'These events book the same venue at overlapping times: %. Reschedule, move or cancel one event of each pair, then run the migration again.', "overlaps";
+	END IF;
+END $$;--> statement-breakpoint
+ALTER TABLE "events" ADD CONSTRAINT "events_venue_no_overlap" EXCLUDE USING gist ("venue_id" WITH =, tsrange("start_time", "end_time", '[)') WITH &&) WHERE ("venue_id" IS NOT NULL AND "status" <> 'cancelled');
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "event_collaborators_event_id_forum_id_unique";
@@ -0,0 +1,3 @@
+DELETE FROM "event_collaborators" "a" USING "event_collaborators" "b"
+WHERE "a"."ctid" < "b"."ctid" AND "a"."event_id" = "b"."event_id" AND "a"."collaborating_forum_id" = "b"."collaborating_forum_id";--> statement-breakpoint
+CREATE UNIQUE INDEX "event_collaborators_event_id_forum_id_unique" ON "event_collaborators" USING btree ("event_id","collaborating_forum_id");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "event_staff_assignments_event_id_user_id_unique";--> statement-breakpoint
+ALTER TABLE "colleges" DROP COLUMN "require_staff_in_charge";
@@ -0,0 +1,5 @@
+ALTER TABLE "colleges" ADD COLUMN "require_staff_in_charge" boolean DEFAULT true NOT NULL;--> statement-breakpoint
+DELETE FROM "event_staff_assignments" "a" USING "event_staff_assignments" "b"
+WHERE "a"."event_id" = "b"."event_id" AND "a"."user_id" = "b"."user_id"
+	AND (coalesce("b"."status" = 'approved', false), "b"."ctid") > (coalesce("a"."status" = 'approved', false), "a"."ctid");--> statement-breakpoint
+CREATE UNIQUE INDEX "event_staff_assignments_event_id_user_id_unique" ON "event_staff_assignments" USING btree ("event_id","user_id");
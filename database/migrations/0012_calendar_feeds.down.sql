@@ -0,0 +1,2 @@
+DROP TABLE "calendar_feed_tokens";--> statement-breakpoint
+ALTER TABLE "events" DROP COLUMN "sequence";
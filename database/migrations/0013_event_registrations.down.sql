@@ -0,0 +1,3 @@
+DROP TABLE "event_registrations";--> statement-breakpoint
+ALTER TABLE "events" DROP CONSTRAINT "events_registration_limit_positive";--> statement-breakpoint
+ALTER TABLE "events" DROP COLUMN "registration_limit";
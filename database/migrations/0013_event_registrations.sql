@@ -0,0 +1,13 @@
+ALTER TABLE "events" ADD COLUMN "registration_limit" integer;--> statement-breakpoint
+ALTER TABLE "events" ADD CONSTRAINT "events_registration_limit_positive" CHECK ("events"."registration_limit" > 0);--> statement-breakpoint
+CREATE TABLE "event_registrations" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"event_id" uuid NOT NULL,
+	"user_id" uuid NOT NULL,
+	"created_at" timestamp DEFAULT now() NOT NULL
+);
+--> statement-breakpoint
+ALTER TABLE "event_registrations" ADD CONSTRAINT "event_registrations_event_id_events_id_fk" FOREIGN KEY ("event_id") REFERENCES "public"."events"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "event_registrations" ADD CONSTRAINT "event_registrations_user_id_users_id_fk" FOREIGN KEY ("user_id") REFERENCES "public"."users"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+CREATE UNIQUE INDEX "event_registrations_event_id_user_id_unique" ON "event_registrations" USING btree ("event_id","user_id");--> statement-breakpoint
+CREATE INDEX "event_registrations_user_id_idx" ON "event_registrations" USING btree ("user_id");
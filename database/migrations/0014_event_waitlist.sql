@@ -0,0 +1,14 @@
+CREATE TABLE "event_waitlist_entries" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"event_id" uuid NOT NULL,
+	"user_id" uuid NOT NULL,
+	"created_at" timestamp DEFAULT now() NOT NULL,
+	"offered_at" timestamp,
+	"offer_expires_at" timestamp
+);
+--> statement-breakpoint
+ALTER TABLE "event_waitlist_entries" ADD CONSTRAINT "event_waitlist_entries_event_id_events_id_fk" FOREIGN KEY ("event_id") REFERENCES "public"."events"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "event_waitlist_entries" ADD CONSTRAINT "event_waitlist_entries_user_id_users_id_fk" FOREIGN KEY ("user_id") REFERENCES "public"."users"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+CREATE UNIQUE INDEX "event_waitlist_entries_event_id_user_id_unique" ON "event_waitlist_entries" USING btree ("event_id","user_id");--> statement-breakpoint
+CREATE INDEX "event_waitlist_entries_event_id_created_at_idx" ON "event_waitlist_entries" USING btree ("event_id","created_at");--> statement-breakpoint
+CREATE INDEX "event_waitlist_entries_offer_expires_at_idx" ON "event_waitlist_entries" USING btree ("offer_expires_at");
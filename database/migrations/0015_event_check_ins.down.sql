@@ -0,0 +1,2 @@
+ALTER TABLE "event_registrations" DROP COLUMN "checked_in_by";--> statement-breakpoint
+ALTER TABLE "event_registrations" DROP COLUMN "checked_in_at";
@@ -0,0 +1,3 @@
+ALTER TABLE "event_registrations" ADD COLUMN "checked_in_at" timestamp;--> statement-breakpoint
+ALTER TABLE "event_registrations" ADD COLUMN "checked_in_by" uuid;--> statement-breakpoint
+ALTER TABLE "event_registrations" ADD CONSTRAINT "event_registrations_checked_in_by_users_id_fk" FOREIGN KEY ("checked_in_by") REFERENCES "public"."users"("id") ON DELETE set null ON UPDATE no action;
@@ -0,0 +1,16 @@
+CREATE TABLE "user_invitations" (
+	"user_id" uuid PRIMARY KEY NOT NULL,
+	"invited_by" uuid,
+	"sent_at" timestamp DEFAULT now() NOT NULL
+);
+--> statement-breakpoint
+ALTER TABLE "user_invitations" ADD CONSTRAINT "user_invitations_user_id_users_id_fk" FOREIGN KEY ("user_id") REFERENCES "public"."users"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "user_invitations" ADD CONSTRAINT "user_invitations_invited_by_super_admins_id_fk" FOREIGN KEY ("invited_by") REFERENCES "public"."super_admins"("id") ON DELETE set null ON UPDATE no action;--> statement-breakpoint
+INSERT INTO "user_invitations" ("user_id", "invited_by", "sent_at")
+SELECT "users"."id", "colleges"."onboarded_by", COALESCE("colleges"."onboarded_at", "users"."created_at")
+FROM "users"
+JOIN "colleges" ON "colleges"."id" = "users"."college_id"
+WHERE "users"."role" = 'college_admin'
+	AND "users"."approval_status" = 'approved'
+	AND NOT "users"."is_email_verified"
+	AND "colleges"."onboarded_at" IS NOT NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "super_admins_email_lower_unique";
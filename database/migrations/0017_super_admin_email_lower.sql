@@ -0,0 +1,11 @@
+DO $$
+DECLARE
+	"duplicates" text;
+BEGIN
+	SELECT string_agg("email", ', ' ORDER BY "email") INTO "duplicates"
+	FROM (SELECT lower("email") AS "email" FROM "super_admins" GROUP BY lower("email") HAVING count(*) > 1) AS "d";
+	IF "duplicates" IS NOT NULL THEN
+		RAISE EXCEPTION 'Several super admins share these email addresses (ignoring case): %. Remove the duplicate accounts, then run the migration again.', "duplicates";
+	END IF;
+END $$;--> statement-breakpoint
+CREATE UNIQUE INDEX "super_admins_email_lower_unique" ON "super_admins" USING btree (lower("email"));
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "otps_user_id_purpose_active_unique";
@@ -0,0 +1,5 @@
+DELETE FROM "otps" "a" USING "otps" "b"
+WHERE "a"."user_id" = "b"."user_id" AND "a"."purpose" = "b"."purpose"
+	AND "a"."consumed_at" IS NULL AND "b"."consumed_at" IS NULL
+	AND ("a"."created_at", "a"."id") < ("b"."created_at", "b"."id");--> statement-breakpoint
+CREATE UNIQUE INDEX "otps_user_id_purpose_active_unique" ON "otps" USING btree ("user_id","purpose") WHERE "consumed_at" IS NULL;
@@ -1,7 +1,7 @@
 -- name: GetUserByEmail :one
--- Check if a user with a given email already exists
+-- Check if a user with a given email already exists (case-insensitive, matches users_email_lower_unique)
 SELECT * FROM users
-WHERE email = $1 LIMIT 1;
+WHERE lower(email) = lower(@email) LIMIT 1;
 
 -- name: GetCollegeByID :one
 -- Get college details to validate the email domain
@@ -26,46 +26,35 @@ INSERT INTO forum_heads (
 )
 RETURNING *;
 
--- name: SetUserEmailVerificationDetails :exec
--- Sets the OTP token and expiration for a user after registration
-UPDATE users
-SET 
-  email_verification_token = $2,
-  email_verification_expires = $3
-WHERE id = $1;
-
 -- name: VerifyUserEmail :one
--- Marks a user's email as verified and clears the token fields
+-- Marks a user's email as verified
 UPDATE users
 SET
-  is_email_verified = true,
-  email_verification_token = NULL,
-  email_verification_expires = NULL
+  is_email_verified = true
 WHERE id = $1
 RETURNING *;
 
 -- name: GetSuperAdminByEmail :one
--- Fetches a super admin for login verification
+-- Fetches a super admin for login verification (case-insensitive)
 SELECT * FROM super_admins
-WHERE email = $1 LIMIT 1;
+WHERE lower(email) = lower(@email) LIMIT 1;
 
--- name: SetUserPasswordResetDetails :exec
--- Sets the user password reset token
+-- name: UpdateUserPassword :exec
+-- Sets the new hashed password for user after reseting
 UPDATE users
 SET
-  password_reset_token=$2,
-  password_reset_expires=$3
+  password_hash=$2
 WHERE id=$1;
 
+-- name: GetUserPasswordHash :one
+SELECT password_hash FROM users
+WHERE id = $1 LIMIT 1;
 
--- name: UpdateUserPassword :exec
--- Sets the new hashed password for user after reseting
+-- name: ResetUserPassword :execrows
+-- Sets a new password only if the old hash still matches, which makes reset tickets single-use
 UPDATE users
-SET
-  password_hash=$2,
-  password_reset_expires=NULL,
-  password_reset_token=NULL
-WHERE id=$1;
+SET password_hash = @new_hash
+WHERE id = @id AND password_hash = @old_hash;
 
 -- name: GetUserByID :one
 SELECT 
@@ -126,4 +115,18 @@ LIMIT 1;
 
 
 -- name: GetSuperAdminByID :one
-SELECT * FROM super_admins WHERE id = $1 LIMIT 1;
\ No newline at end of file
+SELECT * FROM super_admins WHERE id = $1 LIMIT 1;
+
+-- name: CreateSuperAdmin :one
+INSERT INTO super_admins (
+  full_name, email, password_hash
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: UpdateSuperAdminPassword :exec
+UPDATE super_admins
+SET
+  password_hash=$2
+WHERE id=$1;
@@ -0,0 +1,65 @@
+-- name: UpsertCalendarFeedToken :one
+-- Stores a user's feed token, replacing (and so revoking) any previous one
+INSERT INTO calendar_feed_tokens (
+  user_id, token_hash
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET token_hash = EXCLUDED.token_hash, created_at = now()
+RETURNING *;
+
+-- name: DeleteCalendarFeedToken :execrows
+DELETE FROM calendar_feed_tokens
+WHERE user_id = $1;
+
+-- name: GetCalendarFeedOwner :one
+-- Resolves a feed token to its owner, who must still be an approved member
+SELECT users.id, users.college_id
+FROM calendar_feed_tokens
+JOIN users ON users.id = calendar_feed_tokens.user_id
+WHERE calendar_feed_tokens.token_hash = $1
+  AND users.approval_status = 'approved'
+  AND users.is_email_verified = true;
+
+-- name: ListCollegeCalendarEvents :many
+-- Lists the published events of a college, optionally of one forum. Events
+-- cancelled after being confirmed are kept so calendars drop them.
+SELECT
+  events.id, events.name, events.description, events.start_time, events.end_time,
+  events.status, events.updated_at, events.sequence,
+  venues.name AS venue_name, venues.location_details, forums.name AS forum_name
+FROM events
+JOIN forums ON forums.id = events.forum_id
+LEFT JOIN venues ON venues.id = events.venue_id
+WHERE events.college_id = @college_id
+  AND (sqlc.narg(forum_id)::uuid IS NULL OR events.forum_id = sqlc.narg(forum_id)::uuid)
+  AND events.end_time > @since
+  AND (events.status = 'confirmed' OR (events.status = 'cancelled' AND EXISTS (
+    SELECT 1 FROM event_status_transitions
+    WHERE event_status_transitions.event_id = events.id AND event_status_transitions.to_status = 'confirmed'
+  )))
+ORDER BY events.start_time;
+
+-- name: ListUserCalendarEvents :many
+-- Lists the published events a user registered for or is approved staff for
+SELECT
+  events.id, events.name, events.description, events.start_time, events.end_time,
+  events.status, events.updated_at, events.sequence,
+  venues.name AS venue_name, venues.location_details, forums.name AS forum_name
+FROM events
+JOIN forums ON forums.id = events.forum_id
+LEFT JOIN venues ON venues.id = events.venue_id
+WHERE events.id IN (
+    SELECT event_id FROM event_staff_assignments
+    WHERE event_staff_assignments.user_id = @user_id AND event_staff_assignments.status = 'approved'
+    UNION
+    SELECT event_id FROM event_registrations
+    WHERE event_registrations.user_id = @user_id
+  )
+  AND events.end_time > @since
+  AND (events.status = 'confirmed' OR (events.status = 'cancelled' AND EXISTS (
+    SELECT 1 FROM event_status_transitions
+    WHERE event_status_transitions.event_id = events.id AND event_status_transitions.to_status = 'confirmed'
+  )))
+ORDER BY events.start_time;
@@ -0,0 +1,67 @@
+-- name: CreateEventCollaborator :one
+INSERT INTO event_collaborators (
+  event_id, collaborating_forum_id
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: GetEventCollaborator :one
+SELECT * FROM event_collaborators
+WHERE id = $1 LIMIT 1;
+
+-- name: ListEventCollaborators :many
+SELECT
+  event_collaborators.id,
+  event_collaborators.status,
+  event_collaborators.collaborating_forum_id,
+  forums.name AS forum_name,
+  event_collaborators.created_at
+FROM event_collaborators
+JOIN forums ON forums.id = event_collaborators.collaborating_forum_id
+WHERE event_collaborators.event_id = $1
+ORDER BY event_collaborators.created_at;
+
+-- name: ListCollaborationInvites :many
+-- Lists collaboration invitations addressed to forums the user is a verified head of
+SELECT
+  event_collaborators.id,
+  event_collaborators.status,
+  event_collaborators.collaborating_forum_id,
+  event_collaborators.created_at,
+  events.id AS event_id,
+  events.name AS event_name,
+  events.start_time,
+  events.end_time,
+  events.status AS event_status,
+  forums.name AS organizer_forum_name
+FROM event_collaborators
+JOIN forum_heads ON forum_heads.forum_id = event_collaborators.collaborating_forum_id
+JOIN events ON events.id = event_collaborators.event_id
+JOIN forums ON forums.id = events.forum_id
+WHERE forum_heads.user_id = @user_id
+  AND forum_heads.is_verified = true
+  AND (sqlc.narg(status)::collaboration_status IS NULL OR event_collaborators.status = sqlc.narg(status)::collaboration_status)
+ORDER BY events.start_time;
+
+-- name: RespondToCollaboration :one
+-- Accepts or rejects an invitation that is still pending
+UPDATE event_collaborators
+SET status = @status
+WHERE id = @id AND status = 'pending'
+RETURNING *;
+
+-- name: DeleteEventCollaborator :execrows
+DELETE FROM event_collaborators
+WHERE id = @id AND event_id = @event_id;
+
+-- name: IsAcceptedCollaborator :one
+-- Reports whether the user is a verified head of a forum that accepted to co-host the event
+SELECT EXISTS (
+  SELECT 1 FROM event_collaborators
+  JOIN forum_heads ON forum_heads.forum_id = event_collaborators.collaborating_forum_id
+  WHERE event_collaborators.event_id = @event_id
+    AND event_collaborators.status = 'accepted'
+    AND forum_heads.user_id = @user_id
+    AND forum_heads.is_verified = true
+);
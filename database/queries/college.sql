@@ -0,0 +1,92 @@
+-- name: CreateCollege :one
+INSERT INTO colleges (
+  name, domain_name, has_paid
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetCollegeByDomain :one
+SELECT * FROM colleges
+WHERE domain_name = $1 LIMIT 1;
+
+-- name: ListColleges :many
+SELECT * FROM colleges
+ORDER BY name;
+
+-- name: SearchColleges :many
+-- Pages through colleges, optionally filtered by a case-insensitive name match
+SELECT * FROM colleges
+WHERE (@search::text = '' OR strpos(lower(name), lower(@search::text)) > 0)
+ORDER BY name
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountColleges :one
+SELECT count(*) FROM colleges
+WHERE (@search::text = '' OR strpos(lower(name), lower(@search::text)) > 0);
+
+-- name: GetCollegeStats :one
+-- Counts the rows that would be removed together with a college
+SELECT
+  (SELECT count(*) FROM users WHERE users.college_id = @id) AS user_count,
+  (SELECT count(*) FROM events WHERE events.college_id = @id) AS event_count,
+  (SELECT count(*) FROM forums WHERE forums.college_id = @id) AS forum_count,
+  (SELECT count(*) FROM venues WHERE venues.college_id = @id) AS venue_count;
+
+-- name: UpdateCollege :one
+-- Updates the given fields of a college, leaving NULL arguments unchanged
+UPDATE colleges
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  domain_name = COALESCE(sqlc.narg(domain_name), domain_name),
+  has_paid = COALESCE(sqlc.narg(has_paid), has_paid),
+  allowed_email_domains = COALESCE(sqlc.narg(allowed_email_domains), allowed_email_domains),
+  email_domain_override = COALESCE(sqlc.narg(email_domain_override), email_domain_override),
+  require_staff_in_charge = COALESCE(sqlc.narg(require_staff_in_charge), require_staff_in_charge),
+  updated_at = now()
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteCollege :execrows
+DELETE FROM colleges
+WHERE id = $1;
+
+-- name: MarkCollegeOnboarded :one
+-- Records which super admin onboarded a college
+UPDATE colleges
+SET
+  onboarded_by = $2,
+  onboarded_at = now(),
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListPublicColleges :many
+-- Pages through paid colleges whose name starts with the given prefix
+SELECT * FROM colleges
+WHERE has_paid = true
+  AND (@prefix::text = '' OR starts_with(lower(name), lower(@prefix::text)))
+ORDER BY name
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountPublicColleges :one
+SELECT count(*) FROM colleges
+WHERE has_paid = true
+  AND (@prefix::text = '' OR starts_with(lower(name), lower(@prefix::text)));
+
+-- name: ListPaidCollegesByEmailDomain :many
+-- Finds paid colleges accepting addresses on the given lower-cased domain or
+-- one of its parent domains
+SELECT * FROM colleges
+WHERE has_paid = true
+  AND email_domain_override = false
+  AND (
+    lower(domain_name) = @domain::text
+    OR right(@domain::text, length(domain_name) + 1) = '.' || lower(domain_name)
+    OR EXISTS (
+      SELECT 1 FROM unnest(allowed_email_domains) AS allowed(domain)
+      WHERE lower(allowed.domain) = @domain::text
+        OR right(@domain::text, length(allowed.domain) + 1) = '.' || lower(allowed.domain)
+    )
+  )
+ORDER BY name;
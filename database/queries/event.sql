@@ -0,0 +1,110 @@
+-- name: CreateEvent :one
+INSERT INTO events (
+  name, description, start_time, end_time, banner_image, resize_mode, registration_link,
+  college_id, venue_id, organizer_id, forum_id, registration_limit
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+RETURNING *;
+
+-- name: GetEventByID :one
+SELECT * FROM events
+WHERE id = $1 LIMIT 1;
+
+-- name: ListCollegeEvents :many
+-- Pages through the events of a college. Unless include_all is set, only confirmed events
+-- and events organized or co-hosted by the given forums are returned.
+SELECT * FROM events
+WHERE college_id = @college_id
+  AND (@include_all::boolean OR status = 'confirmed' OR forum_id = ANY(@visible_forum_ids::uuid[])
+    OR id IN (SELECT event_id FROM event_collaborators WHERE collaborating_forum_id = ANY(@visible_forum_ids::uuid[]) AND event_collaborators.status = 'accepted'))
+  AND (sqlc.narg(status)::event_status IS NULL OR status = sqlc.narg(status)::event_status)
+  AND (sqlc.narg(forum_id)::uuid IS NULL OR forum_id = sqlc.narg(forum_id)::uuid)
+  AND (sqlc.narg(range_start)::timestamp IS NULL OR end_time > sqlc.narg(range_start)::timestamp)
+  AND (sqlc.narg(range_end)::timestamp IS NULL OR start_time < sqlc.narg(range_end)::timestamp)
+ORDER BY start_time
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountCollegeEvents :one
+SELECT count(*) FROM events
+WHERE college_id = @college_id
+  AND (@include_all::boolean OR status = 'confirmed' OR forum_id = ANY(@visible_forum_ids::uuid[])
+    OR id IN (SELECT event_id FROM event_collaborators WHERE collaborating_forum_id = ANY(@visible_forum_ids::uuid[]) AND event_collaborators.status = 'accepted'))
+  AND (sqlc.narg(status)::event_status IS NULL OR status = sqlc.narg(status)::event_status)
+  AND (sqlc.narg(forum_id)::uuid IS NULL OR forum_id = sqlc.narg(forum_id)::uuid)
+  AND (sqlc.narg(range_start)::timestamp IS NULL OR end_time > sqlc.narg(range_start)::timestamp)
+  AND (sqlc.narg(range_end)::timestamp IS NULL OR start_time < sqlc.narg(range_end)::timestamp);
+
+-- name: UpdateEvent :one
+-- Edits an event only while it is still a draft
+UPDATE events
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  description = COALESCE(sqlc.narg(description), description),
+  start_time = COALESCE(sqlc.narg(start_time), start_time),
+  end_time = COALESCE(sqlc.narg(end_time), end_time),
+  banner_image = COALESCE(sqlc.narg(banner_image), banner_image),
+  resize_mode = COALESCE(sqlc.narg(resize_mode), resize_mode),
+  registration_link = COALESCE(sqlc.narg(registration_link), registration_link),
+  venue_id = CASE WHEN @clear_venue::boolean THEN NULL ELSE COALESCE(sqlc.narg(venue_id), venue_id) END,
+  registration_limit = CASE WHEN @clear_registration_limit::boolean THEN NULL
+    ELSE COALESCE(sqlc.narg(registration_limit), registration_limit) END,
+  sequence = sequence + 1,
+  updated_at = now()
+WHERE id = @id AND status = 'draft'
+RETURNING *;
+
+-- name: SetEventStatus :one
+-- Moves an event to a new status only if it is still in the expected one
+UPDATE events
+SET status = @to_status, sequence = sequence + 1, updated_at = now()
+WHERE id = @id AND status = @from_status
+RETURNING *;
+
+-- name: CreateEventTransition :exec
+INSERT INTO event_status_transitions (
+  event_id, from_status, to_status, actor_id, note
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: ListEventTransitions :many
+SELECT
+  event_status_transitions.id,
+  event_status_transitions.from_status,
+  event_status_transitions.to_status,
+  event_status_transitions.actor_id,
+  users.full_name AS actor_name,
+  event_status_transitions.note,
+  event_status_transitions.created_at
+FROM event_status_transitions
+LEFT JOIN users ON users.id = event_status_transitions.actor_id
+WHERE event_status_transitions.event_id = $1
+ORDER BY event_status_transitions.created_at, event_status_transitions.id;
+
+-- name: IsApprovedEventStaff :one
+SELECT EXISTS (
+  SELECT 1 FROM event_staff_assignments
+  WHERE event_id = @event_id AND user_id = @user_id AND status = 'approved'
+);
+
+-- name: ListVenueConflicts :many
+-- Lists the non-cancelled events other than exclude_event_id holding the venue during [start_time, end_time),
+-- mirroring the events_venue_no_overlap constraint
+SELECT id, name, start_time, end_time, status, forum_id FROM events
+WHERE venue_id = @venue_id
+  AND status <> 'cancelled'
+  AND id <> @exclude_event_id
+  AND tsrange(start_time, end_time, '[)') && tsrange(@start_time::timestamp, @end_time::timestamp, '[)')
+ORDER BY start_time;
+
+-- name: SetEventRegistrationLimit :one
+UPDATE events
+SET registration_limit = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListUpcomingVenueEventIDs :many
+-- Lists the confirmed events at a venue that have not started yet
+SELECT id FROM events
+WHERE venue_id = @venue_id AND status = 'confirmed' AND start_time > @now::timestamp;
@@ -0,0 +1,112 @@
+-- name: GetForumHead :one
+-- Fetches a user's head membership for a forum, if any
+SELECT * FROM forum_heads
+WHERE user_id = $1 AND forum_id = $2 LIMIT 1;
+
+-- name: CreateForum :one
+INSERT INTO forums (
+  name, description, college_id
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetForumByID :one
+SELECT * FROM forums
+WHERE id = $1 LIMIT 1;
+
+-- name: ListCollegeForumHeads :many
+-- Lists forum heads of a college with their user and forum, optionally filtered by forum and verification
+SELECT
+  forum_heads.user_id,
+  forum_heads.forum_id,
+  forum_heads.is_verified,
+  users.full_name,
+  users.email,
+  users.approval_status,
+  forums.name AS forum_name
+FROM forum_heads
+JOIN users ON users.id = forum_heads.user_id
+JOIN forums ON forums.id = forum_heads.forum_id
+WHERE forums.college_id = @college_id
+  AND (sqlc.narg(forum_id)::uuid IS NULL OR forum_heads.forum_id = sqlc.narg(forum_id)::uuid)
+  AND (sqlc.narg(is_verified)::boolean IS NULL OR forum_heads.is_verified = sqlc.narg(is_verified)::boolean)
+ORDER BY forums.name, users.full_name
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountCollegeForumHeads :one
+SELECT count(*) FROM forum_heads
+JOIN forums ON forums.id = forum_heads.forum_id
+WHERE forums.college_id = @college_id
+  AND (sqlc.narg(forum_id)::uuid IS NULL OR forum_heads.forum_id = sqlc.narg(forum_id)::uuid)
+  AND (sqlc.narg(is_verified)::boolean IS NULL OR forum_heads.is_verified = sqlc.narg(is_verified)::boolean);
+
+-- name: SetForumHeadVerified :one
+-- Verifies or revokes a forum head, scoped to forums of the given college
+UPDATE forum_heads
+SET is_verified = @is_verified
+FROM forums
+WHERE forums.id = forum_heads.forum_id
+  AND forums.college_id = @college_id
+  AND forum_heads.user_id = @user_id
+  AND forum_heads.forum_id = @forum_id
+RETURNING forum_heads.*;
+
+-- name: ReassignForumHead :one
+-- Moves a forum head to another forum. The new assignment is made by an admin, so it starts out verified.
+UPDATE forum_heads
+SET forum_id = @new_forum_id, is_verified = true
+WHERE user_id = @user_id AND forum_id = @forum_id
+RETURNING *;
+
+-- name: ListCollegeForums :many
+-- Pages through the forums of a college, optionally including archived ones and filtered by name
+SELECT * FROM forums
+WHERE college_id = @college_id
+  AND (@include_archived::boolean OR archived_at IS NULL)
+  AND (@search::text = '' OR strpos(lower(name), lower(@search::text)) > 0)
+ORDER BY name
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountCollegeForums :one
+SELECT count(*) FROM forums
+WHERE college_id = @college_id
+  AND (@include_archived::boolean OR archived_at IS NULL)
+  AND (@search::text = '' OR strpos(lower(name), lower(@search::text)) > 0);
+
+-- name: ListVerifiedForumHeads :many
+-- Lists the verified heads of the given forums
+SELECT forum_heads.forum_id, users.id AS user_id, users.full_name, users.email
+FROM forum_heads
+JOIN users ON users.id = forum_heads.user_id
+WHERE forum_heads.forum_id = ANY(@forum_ids::uuid[])
+  AND forum_heads.is_verified = true
+ORDER BY users.full_name;
+
+-- name: UpdateForum :one
+UPDATE forums
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  description = COALESCE(sqlc.narg(description), description)
+WHERE id = @id AND college_id = @college_id
+RETURNING *;
+
+-- name: SetForumArchivedAt :one
+-- Archives a forum, or restores it when archived_at is null
+UPDATE forums
+SET archived_at = sqlc.narg(archived_at)
+WHERE id = @id AND college_id = @college_id
+RETURNING *;
+
+-- name: CountForumEvents :one
+SELECT count(*) FROM events
+WHERE forum_id = $1;
+
+-- name: DeleteForum :execrows
+DELETE FROM forums
+WHERE id = @id AND college_id = @college_id;
+
+-- name: ListVerifiedForumIDsForUser :many
+-- Lists the forums a user is a verified head of
+SELECT forum_id FROM forum_heads
+WHERE user_id = $1 AND is_verified = true;
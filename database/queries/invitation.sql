@@ -0,0 +1,21 @@
+-- name: CreateUserInvitation :exec
+INSERT INTO user_invitations (user_id, invited_by)
+VALUES ($1, $2);
+
+-- name: GetInvitedCollegeAdmin :one
+-- Finds a college admin of the given college who has not accepted their invitation yet
+SELECT users.* FROM users
+JOIN user_invitations ON user_invitations.user_id = users.id
+WHERE users.id = @user_id
+  AND users.college_id = @college_id
+  AND users.role = 'college_admin'
+LIMIT 1;
+
+-- name: RenewUserInvitation :exec
+UPDATE user_invitations
+SET invited_by = $2, sent_at = now()
+WHERE user_id = $1;
+
+-- name: DeleteUserInvitation :exec
+DELETE FROM user_invitations
+WHERE user_id = $1;
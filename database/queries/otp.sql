@@ -0,0 +1,46 @@
+-- name: CreateOtp :one
+-- Issues a code, replacing the outstanding one for the same user and purpose in a single
+-- statement so that concurrent requests cannot leave two live codes
+INSERT INTO otps (
+  user_id, purpose, code_hash, max_attempts, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (user_id, purpose) WHERE consumed_at IS NULL DO UPDATE
+SET code_hash = EXCLUDED.code_hash,
+  attempts = 0,
+  max_attempts = EXCLUDED.max_attempts,
+  expires_at = EXCLUDED.expires_at,
+  locked_at = NULL,
+  created_at = now()
+RETURNING *;
+
+-- name: GetActiveOtp :one
+-- Latest code for a purpose that has not been used or superseded yet
+SELECT * FROM otps
+WHERE user_id = $1 AND purpose = $2 AND consumed_at IS NULL
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: RecordOtpAttempt :one
+-- Counts a verification attempt and locks the code once the limit is reached.
+-- Returns no rows if the code was already locked.
+UPDATE otps
+SET
+  attempts = attempts + 1,
+  locked_at = CASE WHEN attempts + 1 >= max_attempts THEN now() ELSE locked_at END
+WHERE id = $1 AND attempts < max_attempts
+RETURNING *;
+
+-- name: ConsumeOtp :execrows
+-- Marks a code as used so it cannot be replayed
+UPDATE otps
+SET consumed_at = now()
+WHERE id = $1 AND consumed_at IS NULL;
+
+-- name: DeleteExpiredOtps :execrows
+-- Removes codes that expired or were used before now, keeping locked codes until
+-- locked_before passes their lock time so that a purge cannot lift a lockout
+DELETE FROM otps
+WHERE (expires_at < @now OR consumed_at < @now)
+  AND (locked_at IS NULL OR locked_at < @locked_before);
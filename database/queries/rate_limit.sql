@@ -0,0 +1,13 @@
+-- name: HitRateLimitBucket :one
+-- Counts a request in a fixed window bucket, starting a new window once the old one has passed
+INSERT INTO rate_limit_buckets (key, hits, reset_at)
+VALUES (@key, 1, now() + (@window_seconds::int * interval '1 second'))
+ON CONFLICT (key) DO UPDATE
+SET
+  hits = CASE WHEN rate_limit_buckets.reset_at <= now() THEN 1 ELSE rate_limit_buckets.hits + 1 END,
+  reset_at = CASE WHEN rate_limit_buckets.reset_at <= now() THEN EXCLUDED.reset_at ELSE rate_limit_buckets.reset_at END
+RETURNING hits, reset_at;
+
+-- name: DeleteExpiredRateLimitBuckets :exec
+DELETE FROM rate_limit_buckets
+WHERE reset_at <= now();
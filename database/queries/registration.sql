@@ -0,0 +1,82 @@
+-- name: LockEventForRegistration :one
+-- Locks the event row so that concurrent registrations are counted one at a time
+SELECT events.name, events.status, events.start_time, events.registration_limit, venues.capacity AS venue_capacity
+FROM events
+LEFT JOIN venues ON venues.id = events.venue_id
+WHERE events.id = $1
+FOR UPDATE OF events;
+
+-- name: CreateEventRegistration :one
+INSERT INTO event_registrations (
+  event_id, user_id
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: GetEventRegistration :one
+SELECT * FROM event_registrations
+WHERE event_id = @event_id AND user_id = @user_id;
+
+-- name: DeleteEventRegistration :execrows
+DELETE FROM event_registrations
+WHERE event_id = @event_id AND user_id = @user_id;
+
+-- name: CountEventRegistrations :one
+SELECT count(*) FROM event_registrations
+WHERE event_id = $1;
+
+-- name: ListEventRegistrations :many
+-- Pages through an event's registrants in registration order
+SELECT
+  event_registrations.id,
+  event_registrations.user_id,
+  users.full_name,
+  users.email,
+  users.role,
+  event_registrations.created_at,
+  event_registrations.checked_in_at
+FROM event_registrations
+JOIN users ON users.id = event_registrations.user_id
+WHERE event_registrations.event_id = @event_id
+ORDER BY event_registrations.created_at, event_registrations.id
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: ListAllEventRegistrations :many
+-- Lists every registrant of an event in registration order, for exports
+SELECT
+  event_registrations.id,
+  event_registrations.user_id,
+  users.full_name,
+  users.email,
+  users.role,
+  event_registrations.created_at,
+  event_registrations.checked_in_at
+FROM event_registrations
+JOIN users ON users.id = event_registrations.user_id
+WHERE event_registrations.event_id = $1
+ORDER BY event_registrations.created_at, event_registrations.id;
+
+-- name: GetRegistrationAttendee :one
+SELECT
+  event_registrations.id,
+  event_registrations.event_id,
+  event_registrations.user_id,
+  users.full_name,
+  users.email,
+  event_registrations.checked_in_at
+FROM event_registrations
+JOIN users ON users.id = event_registrations.user_id
+WHERE event_registrations.id = $1;
+
+-- name: CheckInRegistration :one
+-- Marks a registration as attended unless it already is, so a ticket only
+-- gets its holder in once
+UPDATE event_registrations
+SET checked_in_at = now(), checked_in_by = @checked_in_by
+WHERE id = @id AND event_id = @event_id AND checked_in_at IS NULL
+RETURNING *;
+
+-- name: CountCheckedInRegistrations :one
+SELECT count(*) FROM event_registrations
+WHERE event_id = $1 AND checked_in_at IS NOT NULL;
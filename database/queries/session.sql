@@ -0,0 +1,34 @@
+-- name: CreateSession :one
+-- Starts a new login session holding the hashed refresh token
+INSERT INTO sessions (
+  user_id, is_super_admin, refresh_token_hash, user_agent, ip_address, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetSessionByID :one
+SELECT * FROM sessions
+WHERE id = $1 LIMIT 1;
+
+-- name: RotateSessionToken :execrows
+-- Swaps the refresh token hash, but only if the caller presented the current one
+UPDATE sessions
+SET
+  refresh_token_hash = @new_hash,
+  expires_at = @expires_at,
+  last_used_at = now()
+WHERE id = @id
+  AND refresh_token_hash = @old_hash
+  AND revoked_at IS NULL;
+
+-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :exec
+-- Revokes every active session of a user (logout from all devices)
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
@@ -0,0 +1,66 @@
+-- name: CreateStaffAssignment :one
+INSERT INTO event_staff_assignments (
+  event_id, user_id, assignment_role
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetStaffAssignment :one
+SELECT * FROM event_staff_assignments
+WHERE id = $1 LIMIT 1;
+
+-- name: ListEventStaff :many
+SELECT
+  event_staff_assignments.id,
+  event_staff_assignments.assignment_role,
+  event_staff_assignments.status,
+  event_staff_assignments.user_id,
+  users.full_name,
+  users.email,
+  event_staff_assignments.created_at
+FROM event_staff_assignments
+JOIN users ON users.id = event_staff_assignments.user_id
+WHERE event_staff_assignments.event_id = $1
+ORDER BY event_staff_assignments.created_at;
+
+-- name: ListStaffRequestsForUser :many
+-- Lists the staff-in-charge requests addressed to a teacher
+SELECT
+  event_staff_assignments.id,
+  event_staff_assignments.assignment_role,
+  event_staff_assignments.status,
+  event_staff_assignments.created_at,
+  events.id AS event_id,
+  events.name AS event_name,
+  events.start_time,
+  events.end_time,
+  events.status AS event_status,
+  forums.name AS forum_name
+FROM event_staff_assignments
+JOIN events ON events.id = event_staff_assignments.event_id
+JOIN forums ON forums.id = events.forum_id
+WHERE event_staff_assignments.user_id = @user_id
+  AND (sqlc.narg(status)::approval_status IS NULL OR event_staff_assignments.status = sqlc.narg(status)::approval_status)
+ORDER BY events.start_time;
+
+-- name: RespondToStaffAssignment :one
+-- Accepts or declines a request that is still pending; only the requested teacher may answer
+UPDATE event_staff_assignments
+SET status = @status
+WHERE id = @id AND user_id = @user_id AND status = 'pending'
+RETURNING *;
+
+-- name: LockEventStatus :one
+-- Locks the event row so that staff removals and confirmations are checked one at a time
+SELECT status FROM events
+WHERE id = $1
+FOR UPDATE;
+
+-- name: DeleteStaffAssignment :execrows
+DELETE FROM event_staff_assignments
+WHERE id = @id AND event_id = @event_id;
+
+-- name: CountApprovedEventStaff :one
+SELECT count(*) FROM event_staff_assignments
+WHERE event_id = $1 AND status = 'approved';
@@ -0,0 +1,32 @@
+-- name: ListPendingUsers :many
+-- Pages through the users of a college that are waiting for approval, optionally filtered by role
+SELECT id, full_name, email, role, is_email_verified, created_at FROM users
+WHERE college_id = @college_id
+  AND approval_status = 'pending'
+  AND (sqlc.narg(role)::user_role IS NULL OR role = sqlc.narg(role)::user_role)
+ORDER BY created_at
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountPendingUsers :one
+SELECT count(*) FROM users
+WHERE college_id = @college_id
+  AND approval_status = 'pending'
+  AND (sqlc.narg(role)::user_role IS NULL OR role = sqlc.narg(role)::user_role);
+
+-- name: DecidePendingUser :one
+-- Approves or rejects a pending user of the given college. Users that were already decided are left alone.
+UPDATE users
+SET approval_status = @approval_status
+WHERE id = @id
+  AND college_id = @college_id
+  AND approval_status = 'pending'
+RETURNING *;
+
+-- name: ApprovePendingUsers :many
+-- Approves every listed user that is still pending in the given college
+UPDATE users
+SET approval_status = 'approved'
+WHERE id = ANY(@user_ids::uuid[])
+  AND college_id = @college_id
+  AND approval_status = 'pending'
+RETURNING *;
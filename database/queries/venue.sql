@@ -0,0 +1,54 @@
+-- name: GetVenueByID :one
+SELECT * FROM venues
+WHERE id = $1 LIMIT 1;
+
+-- name: CreateVenue :one
+INSERT INTO venues (
+  name, capacity, location_details, college_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListCollegeVenues :many
+-- Pages through the venues of a college, optionally including inactive ones and filtered by name
+SELECT * FROM venues
+WHERE college_id = @college_id
+  AND (@include_inactive::boolean OR is_active IS NOT FALSE)
+  AND (@search::text = '' OR strpos(lower(name), lower(@search::text)) > 0)
+ORDER BY name
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountCollegeVenues :one
+SELECT count(*) FROM venues
+WHERE college_id = @college_id
+  AND (@include_inactive::boolean OR is_active IS NOT FALSE)
+  AND (@search::text = '' OR strpos(lower(name), lower(@search::text)) > 0);
+
+-- name: UpdateVenue :one
+UPDATE venues
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  capacity = COALESCE(sqlc.narg(capacity), capacity),
+  location_details = COALESCE(sqlc.narg(location_details), location_details),
+  is_active = COALESCE(sqlc.narg(is_active), is_active)
+WHERE id = @id AND college_id = @college_id
+RETURNING *;
+
+-- name: CountVenueEvents :one
+SELECT count(*) FROM events
+WHERE venue_id = $1;
+
+-- name: DeleteVenue :execrows
+DELETE FROM venues
+WHERE id = @id AND college_id = @college_id;
+
+-- name: ListVenueBookings :many
+-- Lists the non-cancelled events holding a venue at some point in [range_start, range_end),
+-- mirroring the events_venue_no_overlap constraint
+SELECT id, name, start_time, end_time, status FROM events
+WHERE venue_id = @venue_id
+  AND status <> 'cancelled'
+  AND end_time > @range_start
+  AND start_time < @range_end
+ORDER BY start_time;
@@ -0,0 +1,76 @@
+-- name: CreateWaitlistEntry :one
+INSERT INTO event_waitlist_entries (
+  event_id, user_id
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: GetWaitlistEntry :one
+SELECT * FROM event_waitlist_entries
+WHERE event_id = @event_id AND user_id = @user_id;
+
+-- name: DeleteWaitlistEntry :one
+DELETE FROM event_waitlist_entries
+WHERE event_id = @event_id AND user_id = @user_id
+RETURNING *;
+
+-- name: GetWaitlistPosition :one
+-- Counts the people still waiting up to and including the given entry
+SELECT count(*) AS position
+FROM event_waitlist_entries AS waiting, event_waitlist_entries AS entry
+WHERE entry.id = @entry_id
+  AND waiting.event_id = entry.event_id
+  AND waiting.offered_at IS NULL
+  AND (waiting.created_at, waiting.id) <= (entry.created_at, entry.id);
+
+-- name: CountWaitingEntries :one
+-- Counts the people waiting for a spot, not counting those holding an offer
+SELECT count(*) FROM event_waitlist_entries
+WHERE event_id = $1 AND offered_at IS NULL;
+
+-- name: CountActiveWaitlistOffers :one
+SELECT count(*) FROM event_waitlist_entries
+WHERE event_id = @event_id AND offer_expires_at > @now::timestamp;
+
+-- name: DeleteExpiredWaitlistOffers :execrows
+-- Drops the entries whose offer ran out, so their spot passes to the next in line
+DELETE FROM event_waitlist_entries
+WHERE event_id = @event_id AND offer_expires_at <= @now::timestamp;
+
+-- name: ListNextWaitlistEntries :many
+-- Lists the longest waiting entries that have not been offered a spot yet
+SELECT
+  event_waitlist_entries.id,
+  event_waitlist_entries.user_id,
+  users.full_name,
+  users.email
+FROM event_waitlist_entries
+JOIN users ON users.id = event_waitlist_entries.user_id
+WHERE event_waitlist_entries.event_id = @event_id
+  AND event_waitlist_entries.offered_at IS NULL
+ORDER BY event_waitlist_entries.created_at, event_waitlist_entries.id
+LIMIT @max_entries;
+
+-- name: OfferWaitlistSpots :execrows
+UPDATE event_waitlist_entries
+SET offered_at = now(), offer_expires_at = @offer_expires_at
+WHERE id = ANY(@ids::uuid[]);
+
+-- name: ListEventWaitlist :many
+SELECT
+  event_waitlist_entries.id,
+  event_waitlist_entries.user_id,
+  users.full_name,
+  users.email,
+  event_waitlist_entries.created_at,
+  event_waitlist_entries.offered_at,
+  event_waitlist_entries.offer_expires_at
+FROM event_waitlist_entries
+JOIN users ON users.id = event_waitlist_entries.user_id
+WHERE event_waitlist_entries.event_id = $1
+ORDER BY event_waitlist_entries.created_at, event_waitlist_entries.id;
+
+-- name: ListEventsWithExpiredOffers :many
+SELECT DISTINCT event_id FROM event_waitlist_entries
+WHERE offer_expires_at <= @now::timestamp;
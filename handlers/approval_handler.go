@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+	"unibook-go/util"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxBulkApprove caps how many users can be approved in a single request.
+const maxBulkApprove = 100
+
+type RejectUserPayload struct {
+	Reason string `json:"reason"`
+}
+
+type ApproveUsersPayload struct {
+	UserIDs []uuid.UUID `json:"userIds"`
+}
+
+func pendingUserResponse(user db.User) fiber.Map {
+	return fiber.Map{
+		"id":             user.ID,
+		"fullName":       user.FullName,
+		"email":          user.Email,
+		"role":           user.Role,
+		"approvalStatus": user.ApprovalStatus,
+	}
+}
+
+func parseUserRole(role string) (db.UserRole, bool) {
+	switch r := db.UserRole(role); r {
+	case db.UserRoleCollegeAdmin, db.UserRoleForumHead, db.UserRoleTeacher, db.UserRoleStudent:
+		return r, true
+	}
+	return "", false
+}
+
+// ListPendingUsers lists the users of the admin's college waiting for approval.
+func ListPendingUsers(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	page, limit := parsePagination(c)
+
+	var role db.NullUserRole
+	if r := c.Query("role"); r != "" {
+		parsed, ok := parseUserRole(r)
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid role"})
+		}
+		role = db.NullUserRole{UserRole: parsed, Valid: true}
+	}
+
+	queries := db.New(database.DB)
+
+	users, err := queries.ListPendingUsers(c.Context(), db.ListPendingUsersParams{
+		CollegeID:  *authUser.CollegeID,
+		Role:       role,
+		PageLimit:  int32(limit),
+		PageOffset: int32((page - 1) * limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch pending users"})
+	}
+
+	total, err := queries.CountPendingUsers(c.Context(), db.CountPendingUsersParams{
+		CollegeID: *authUser.CollegeID,
+		Role:      role,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch pending users"})
+	}
+
+	items := make([]fiber.Map, 0, len(users))
+	for _, user := range users {
+		items = append(items, fiber.Map{
+			"id":              user.ID,
+			"fullName":        user.FullName,
+			"email":           user.Email,
+			"role":            user.Role,
+			"isEmailVerified": user.IsEmailVerified,
+			"createdAt":       user.CreatedAt,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"users": items,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// decidePendingUser sets the approval status of a pending user in the admin's
+// college and emails them the outcome.
+func decidePendingUser(c *fiber.Ctx, cfg *config.Config, status db.ApprovalStatus, reason string) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	user, err := db.New(database.DB).DecidePendingUser(c.Context(), db.DecidePendingUserParams{
+		ApprovalStatus: status,
+		ID:             userID,
+		CollegeID:      *authUser.CollegeID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "No pending user found with this ID"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update user"})
+	}
+
+	go util.SendApprovalDecisionEmail(cfg, user.Email, user.FullName, status == db.ApprovalStatusApproved, reason)
+
+	return c.JSON(pendingUserResponse(user))
+}
+
+func ApproveUser(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return decidePendingUser(c, cfg, db.ApprovalStatusApproved, "")
+	}
+}
+
+func RejectUser(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var payload RejectUserPayload
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&payload); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+			}
+		}
+		return decidePendingUser(c, cfg, db.ApprovalStatusRejected, strings.TrimSpace(payload.Reason))
+	}
+}
+
+// ApproveUsers approves several pending users at once. IDs that are unknown,
+// outside the admin's college or no longer pending are skipped.
+func ApproveUsers(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+
+		var payload ApproveUsersPayload
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+		}
+		if len(payload.UserIDs) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "userIds is required"})
+		}
+		if len(payload.UserIDs) > maxBulkApprove {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Too many users in one request"})
+		}
+
+		users, err := db.New(database.DB).ApprovePendingUsers(c.Context(), db.ApprovePendingUsersParams{
+			UserIds:   payload.UserIDs,
+			CollegeID: *authUser.CollegeID,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to approve users"})
+		}
+
+		approved := make([]fiber.Map, 0, len(users))
+		for _, user := range users {
+			go util.SendApprovalDecisionEmail(cfg, user.Email, user.FullName, true, "")
+			approved = append(approved, pendingUserResponse(user))
+		}
+
+		return c.JSON(fiber.Map{
+			"approved": approved,
+			"skipped":  len(payload.UserIDs) - len(users),
+		})
+	}
+}
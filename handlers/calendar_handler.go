@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/ical"
+	"unibook-go/middleware"
+	"unibook-go/util"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// calendarFeedHistory is how far back feeds reach, so that past events stay in
+// subscribers' calendars for a while without feeds growing forever.
+const calendarFeedHistory = 90 * 24 * time.Hour
+
+// calendarFeedURLs lists the feeds a token gives access to. The forum feed is a
+// template: the client substitutes a forum ID for ":forumId".
+func calendarFeedURLs(c *fiber.Ctx, token string) fiber.Map {
+	base := c.BaseURL() + "/api/v1/calendar/" + token
+	return fiber.Map{
+		"college":  base + "/college.ics",
+		"forum":    base + "/forums/:forumId.ics",
+		"personal": base + "/personal.ics",
+	}
+}
+
+// RotateCalendarToken issues a new secret feed token for the user. Any previous
+// token stops working, so subscriptions have to be re-added with the new URLs.
+func RotateCalendarToken(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	token, err := util.GenerateSecureToken(32)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create feed token"})
+	}
+
+	_, err = db.New(database.DB).UpsertCalendarFeedToken(c.Context(), db.UpsertCalendarFeedTokenParams{
+		UserID:    authUser.ID,
+		TokenHash: util.HashToken(token),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create feed token"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"token": token,
+		"feeds": calendarFeedURLs(c, token),
+	})
+}
+
+// RevokeCalendarToken disables the user's calendar feeds.
+func RevokeCalendarToken(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	rows, err := db.New(database.DB).DeleteCalendarFeedToken(c.Context(), authUser.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to revoke feed token"})
+	}
+	if rows == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "No calendar feed token to revoke"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Calendar feed token revoked."})
+}
+
+var errFeedNotFound = errors.New("calendar feed not found")
+
+// calendarFeedOwner resolves the :token path parameter to the user it belongs to.
+func calendarFeedOwner(c *fiber.Ctx) (db.GetCalendarFeedOwnerRow, error) {
+	token := c.Params("token")
+	if token == "" {
+		return db.GetCalendarFeedOwnerRow{}, errFeedNotFound
+	}
+
+	owner, err := db.New(database.DB).GetCalendarFeedOwner(c.Context(), util.HashToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.GetCalendarFeedOwnerRow{}, errFeedNotFound
+	}
+	return owner, err
+}
+
+// feedLookupErrorResponse maps calendarFeedOwner errors. Unknown and revoked
+// tokens look the same so that tokens cannot be probed.
+func feedLookupErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errFeedNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Calendar feed not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load calendar feed"})
+}
+
+func calendarFeedSince() pgtype.Timestamp {
+	return eventTimestamp(time.Now().Add(-calendarFeedHistory))
+}
+
+// calendarEvent converts a feed row to a VEVENT. The UID is derived from the
+// event ID so that every feed containing the event agrees on it.
+func calendarEvent(cfg *config.Config, uidDomain string, row db.ListCollegeCalendarEventsRow) ical.Event {
+	location := make([]string, 0, 2)
+	if row.VenueName.Valid {
+		location = append(location, row.VenueName.String)
+	}
+	if row.LocationDetails.Valid && row.LocationDetails.String != "" {
+		location = append(location, row.LocationDetails.String)
+	}
+
+	description := "Hosted by " + row.ForumName
+	if row.Description.Valid && row.Description.String != "" {
+		description = row.Description.String + "\n\n" + description
+	}
+
+	lastModified := row.UpdatedAt.Time
+	if !row.UpdatedAt.Valid {
+		lastModified = time.Now()
+	}
+
+	return ical.Event{
+		UID:          "event-" + row.ID.String() + "@" + uidDomain,
+		Sequence:     int(row.Sequence),
+		LastModified: lastModified,
+		Start:        row.StartTime.Time,
+		End:          row.EndTime.Time,
+		Summary:      row.Name,
+		Description:  description,
+		Location:     strings.Join(location, ", "),
+		URL:          cfg.AppURL + "/events/" + row.ID.String(),
+		Cancelled:    row.Status == db.EventStatusCancelled,
+	}
+}
+
+// sendCalendar writes rows as an iCalendar document.
+func sendCalendar(c *fiber.Ctx, cfg *config.Config, name string, rows []db.ListCollegeCalendarEventsRow) error {
+	uidDomain := "unibook"
+	if u, err := url.Parse(cfg.AppURL); err == nil && u.Hostname() != "" {
+		uidDomain = u.Hostname()
+	}
+
+	cal := ical.Calendar{Name: name, Events: make([]ical.Event, 0, len(rows))}
+	for _, row := range rows {
+		cal.Events = append(cal.Events, calendarEvent(cfg, uidDomain, row))
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return c.SendString(cal.Encode())
+}
+
+// CollegeCalendarFeed serves the confirmed events of the token owner's college.
+func CollegeCalendarFeed(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		owner, err := calendarFeedOwner(c)
+		if err != nil {
+			return feedLookupErrorResponse(c, err)
+		}
+
+		rows, err := db.New(database.DB).ListCollegeCalendarEvents(c.Context(), db.ListCollegeCalendarEventsParams{
+			CollegeID: owner.CollegeID,
+			Since:     calendarFeedSince(),
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch events"})
+		}
+
+		return sendCalendar(c, cfg, "College events", rows)
+	}
+}
+
+// ForumCalendarFeed serves the confirmed events of one forum of the token
+// owner's college.
+func ForumCalendarFeed(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		owner, err := calendarFeedOwner(c)
+		if err != nil {
+			return feedLookupErrorResponse(c, err)
+		}
+
+		forumID, err := uuid.Parse(c.Params("forumId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum ID"})
+		}
+
+		queries := db.New(database.DB)
+		forum, err := queries.GetForumByID(c.Context(), forumID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch forum"})
+		}
+		if err != nil || forum.CollegeID != owner.CollegeID {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Forum not found"})
+		}
+
+		rows, err := queries.ListCollegeCalendarEvents(c.Context(), db.ListCollegeCalendarEventsParams{
+			CollegeID: owner.CollegeID,
+			ForumID:   pgtype.UUID{Bytes: forum.ID, Valid: true},
+			Since:     calendarFeedSince(),
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch events"})
+		}
+
+		return sendCalendar(c, cfg, forum.Name, rows)
+	}
+}
+
+// PersonalCalendarFeed serves the confirmed events the token owner registered
+// for or is staff of.
+func PersonalCalendarFeed(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		owner, err := calendarFeedOwner(c)
+		if err != nil {
+			return feedLookupErrorResponse(c, err)
+		}
+
+		userRows, err := db.New(database.DB).ListUserCalendarEvents(c.Context(), db.ListUserCalendarEventsParams{
+			UserID: owner.ID,
+			Since:  calendarFeedSince(),
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch events"})
+		}
+
+		rows := make([]db.ListCollegeCalendarEventsRow, 0, len(userRows))
+		for _, row := range userRows {
+			rows = append(rows, db.ListCollegeCalendarEventsRow(row))
+		}
+
+		return sendCalendar(c, cfg, "My events", rows)
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type InviteCollaboratorPayload struct {
+	ForumID uuid.UUID `json:"forumId"`
+}
+
+func collaboratorResponse(collaborator db.ListEventCollaboratorsRow) fiber.Map {
+	return fiber.Map{
+		"id":        collaborator.ID,
+		"forumId":   collaborator.CollaboratingForumID,
+		"forumName": collaborator.ForumName,
+		"status":    collaborator.Status,
+		"createdAt": collaborator.CreatedAt,
+	}
+}
+
+// InviteCollaborator lets the organizing forum invite another forum of the
+// same college to co-host an event.
+func InviteCollaborator(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	queries := db.New(database.DB)
+
+	event, err := getCollegeEvent(c, queries)
+	if err != nil {
+		return eventLookupErrorResponse(c, err)
+	}
+	if !middleware.IsVerifiedForumHead(c.Context(), queries, authUser, event.ForumID) {
+		return middleware.Forbidden(c, "Only verified heads of the organizing forum can invite co-hosts.")
+	}
+	if event.Status == db.EventStatusCancelled {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Cancelled events cannot take new co-hosts.",
+			"code":  "EVENT_CANCELLED",
+		})
+	}
+
+	var payload InviteCollaboratorPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+	if payload.ForumID == event.ForumID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "A forum cannot co-host its own event"})
+	}
+
+	forum, err := queries.GetForumByID(c.Context(), payload.ForumID)
+	if err != nil || forum.CollegeID != event.CollegeID || forum.ArchivedAt.Valid {
+		return invalidForumResponse(c)
+	}
+
+	collaborator, err := queries.CreateEventCollaborator(c.Context(), db.CreateEventCollaboratorParams{
+		EventID:              event.ID,
+		CollaboratingForumID: forum.ID,
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "This forum has already been invited.",
+				"code":  "ALREADY_INVITED",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to invite forum"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(collaboratorResponse(db.ListEventCollaboratorsRow{
+		ID:                   collaborator.ID,
+		Status:               collaborator.Status,
+		CollaboratingForumID: collaborator.CollaboratingForumID,
+		ForumName:            forum.Name,
+		CreatedAt:            collaborator.CreatedAt,
+	}))
+}
+
+// RemoveCollaborator withdraws an invitation or removes a co-host.
+func RemoveCollaborator(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	queries := db.New(database.DB)
+
+	event, err := getCollegeEvent(c, queries)
+	if err != nil {
+		return eventLookupErrorResponse(c, err)
+	}
+	if !middleware.IsVerifiedForumHead(c.Context(), queries, authUser, event.ForumID) {
+		return middleware.Forbidden(c, "Only verified heads of the organizing forum can remove co-hosts.")
+	}
+
+	collaboratorID, err := uuid.Parse(c.Params("collaboratorId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid collaborator ID"})
+	}
+
+	rows, err := queries.DeleteEventCollaborator(c.Context(), db.DeleteEventCollaboratorParams{
+		ID:      collaboratorID,
+		EventID: event.ID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to remove collaborator"})
+	}
+	if rows == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Collaborator not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Collaborator removed successfully."})
+}
+
+// ListCollaborationInvites lists the invitations addressed to forums the user
+// heads. Filter with ?status=pending|accepted|rejected.
+func ListCollaborationInvites(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	var status db.NullCollaborationStatus
+	if s := c.Query("status"); s != "" {
+		switch cs := db.CollaborationStatus(s); cs {
+		case db.CollaborationStatusPending, db.CollaborationStatusAccepted, db.CollaborationStatusRejected:
+			status = db.NullCollaborationStatus{CollaborationStatus: cs, Valid: true}
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid status"})
+		}
+	}
+
+	invites, err := db.New(database.DB).ListCollaborationInvites(c.Context(), db.ListCollaborationInvitesParams{
+		UserID: authUser.ID,
+		Status: status,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch invitations"})
+	}
+
+	items := make([]fiber.Map, 0, len(invites))
+	for _, invite := range invites {
+		items = append(items, fiber.Map{
+			"id":      invite.ID,
+			"status":  invite.Status,
+			"forumId": invite.CollaboratingForumID,
+			"event": fiber.Map{
+				"id":                 invite.EventID,
+				"name":               invite.EventName,
+				"startTime":          invite.StartTime,
+				"endTime":            invite.EndTime,
+				"status":             invite.EventStatus,
+				"organizerForumName": invite.OrganizerForumName,
+			},
+			"createdAt": invite.CreatedAt,
+		})
+	}
+
+	return c.JSON(fiber.Map{"invitations": items})
+}
+
+// respondToCollaboration returns a handler with which the invited forum's
+// verified heads accept or reject an invitation.
+func respondToCollaboration(status db.CollaborationStatus) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+		queries := db.New(database.DB)
+
+		collaboratorID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid invitation ID"})
+		}
+
+		invite, err := queries.GetEventCollaborator(c.Context(), collaboratorID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Invitation not found"})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch invitation"})
+		}
+		if !middleware.IsVerifiedForumHead(c.Context(), queries, authUser, invite.CollaboratingForumID) {
+			return middleware.Forbidden(c, "Only verified heads of the invited forum can respond.")
+		}
+
+		updated, err := queries.RespondToCollaboration(c.Context(), db.RespondToCollaborationParams{
+			Status: status,
+			ID:     invite.ID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error":  "This invitation has already been answered.",
+					"code":   "ALREADY_ANSWERED",
+					"status": invite.Status,
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to respond to invitation"})
+		}
+
+		return c.JSON(fiber.Map{
+			"id":      updated.ID,
+			"eventId": updated.EventID,
+			"forumId": updated.CollaboratingForumID,
+			"status":  updated.Status,
+		})
+	}
+}
+
+var (
+	AcceptCollaboration = respondToCollaboration(db.CollaborationStatusAccepted)
+	RejectCollaboration = respondToCollaboration(db.CollaborationStatusRejected)
+)
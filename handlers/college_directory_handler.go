@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"strings"
+
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// directoryCollegeResponse is the public view of a college: enough to pick it
+// while registering, without exposing billing or onboarding details.
+func directoryCollegeResponse(college db.College) fiber.Map {
+	domains := collegeEmailDomains(college)
+	if domains == nil {
+		domains = []string{}
+	}
+
+	var domainHint *string
+	if len(domains) > 0 {
+		hint := "@" + domains[0]
+		domainHint = &hint
+	}
+
+	return fiber.Map{
+		"id":           college.ID,
+		"name":         college.Name,
+		"domainHint":   domainHint,
+		"emailDomains": domains,
+	}
+}
+
+// ListPublicColleges lists paid colleges, optionally narrowed to names starting
+// with the "search" prefix, so the registration form can offer a picker.
+func ListPublicColleges(c *fiber.Ctx) error {
+	page, limit := parsePagination(c)
+	prefix := strings.TrimSpace(c.Query("search"))
+
+	queries := db.New(database.DB)
+	colleges, err := queries.ListPublicColleges(c.Context(), db.ListPublicCollegesParams{
+		Prefix:     prefix,
+		PageLimit:  int32(limit),
+		PageOffset: int32((page - 1) * limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch colleges"})
+	}
+
+	total, err := queries.CountPublicColleges(c.Context(), prefix)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count colleges"})
+	}
+
+	items := make([]fiber.Map, 0, len(colleges))
+	for _, college := range colleges {
+		items = append(items, directoryCollegeResponse(college))
+	}
+
+	return c.JSON(fiber.Map{
+		"colleges": items,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// LookupCollegeByEmail suggests the colleges accepting the domain of the
+// "email" query parameter (or a bare "domain"). "college" is set when the
+// match is unambiguous so the client can preselect it.
+func LookupCollegeByEmail(c *fiber.Ctx) error {
+	var domain string
+	if email := strings.TrimSpace(c.Query("email")); email != "" {
+		d, ok := util.EmailDomain(email)
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid email address"})
+		}
+		domain = d
+	} else {
+		domain = strings.ToLower(strings.TrimSpace(c.Query("domain")))
+	}
+	if domain == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "email or domain is required"})
+	}
+
+	colleges, err := db.New(database.DB).ListPaidCollegesByEmailDomain(c.Context(), domain)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to look up college"})
+	}
+
+	items := make([]fiber.Map, 0, len(colleges))
+	for _, college := range colleges {
+		items = append(items, directoryCollegeResponse(college))
+	}
+
+	var suggested fiber.Map
+	if len(items) == 1 {
+		suggested = items[0]
+	}
+
+	return c.JSON(fiber.Map{
+		"domain":   domain,
+		"college":  suggested,
+		"colleges": items,
+	})
+}
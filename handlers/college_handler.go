@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"unibook-go/database"
+	db "unibook-go/database/db"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateCollegePayload struct {
+	Name                string   `json:"name"`
+	DomainName          string   `json:"domainName"`
+	HasPaid             bool     `json:"hasPaid"`
+	AllowedEmailDomains []string `json:"allowedEmailDomains"`
+	EmailDomainOverride bool     `json:"emailDomainOverride"`
+}
+
+type UpdateCollegePayload struct {
+	Name                *string   `json:"name"`
+	DomainName          *string   `json:"domainName"`
+	HasPaid             *bool     `json:"hasPaid"`
+	AllowedEmailDomains *[]string `json:"allowedEmailDomains"`
+	EmailDomainOverride *bool     `json:"emailDomainOverride"`
+	// RequireStaffInCharge makes events need an approved staff in charge before
+	// they can be confirmed.
+	RequireStaffInCharge *bool `json:"requireStaffInCharge"`
+}
+
+func collegeResponse(college db.College) fiber.Map {
+	var domainName *string
+	if college.DomainName.Valid {
+		domainName = &college.DomainName.String
+	}
+	allowedEmailDomains := college.AllowedEmailDomains
+	if allowedEmailDomains == nil {
+		allowedEmailDomains = []string{}
+	}
+
+	return fiber.Map{
+		"id":                   college.ID,
+		"name":                 college.Name,
+		"domainName":           domainName,
+		"hasPaid":              college.HasPaid,
+		"allowedEmailDomains":  allowedEmailDomains,
+		"emailDomainOverride":  college.EmailDomainOverride,
+		"requireStaffInCharge": college.RequireStaffInCharge,
+		"onboardedBy":          college.OnboardedBy,
+		"onboardedAt":          college.OnboardedAt,
+		"createdAt":            college.CreatedAt,
+		"updatedAt":            college.UpdatedAt,
+	}
+}
+
+func normalizeDomain(domain string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "@")
+}
+
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		if d := normalizeDomain(domain); d != "" {
+			normalized = append(normalized, d)
+		}
+	}
+	return normalized
+}
+
+func domainTakenResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error": "Another college already uses this domain.",
+		"code":  "DOMAIN_TAKEN",
+	})
+}
+
+func ListColleges(c *fiber.Ctx) error {
+	page, limit := parsePagination(c)
+	search := strings.TrimSpace(c.Query("search"))
+
+	queries := db.New(database.DB)
+
+	colleges, err := queries.SearchColleges(c.Context(), db.SearchCollegesParams{
+		Search:     search,
+		PageLimit:  int32(limit),
+		PageOffset: int32((page - 1) * limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch colleges"})
+	}
+
+	total, err := queries.CountColleges(c.Context(), search)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch colleges"})
+	}
+
+	items := make([]fiber.Map, 0, len(colleges))
+	for _, college := range colleges {
+		items = append(items, collegeResponse(college))
+	}
+
+	return c.JSON(fiber.Map{
+		"colleges": items,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// errDomainTaken is returned by insertCollege when the domain is already in use.
+var errDomainTaken = errors.New("college domain already in use")
+
+// insertCollege validates payload and creates the college with qtx, which is
+// expected to be bound to a transaction.
+func insertCollege(c *fiber.Ctx, qtx *db.Queries, payload CreateCollegePayload) (db.College, error) {
+	domain := normalizeDomain(payload.DomainName)
+	domainName := pgtype.Text{String: domain, Valid: domain != ""}
+	if domainName.Valid {
+		if _, err := qtx.GetCollegeByDomain(c.Context(), domainName); err == nil {
+			return db.College{}, errDomainTaken
+		}
+	}
+
+	college, err := qtx.CreateCollege(c.Context(), db.CreateCollegeParams{
+		Name:       strings.TrimSpace(payload.Name),
+		DomainName: domainName,
+		HasPaid:    payload.HasPaid,
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return db.College{}, errDomainTaken
+		}
+		return db.College{}, err
+	}
+
+	if len(payload.AllowedEmailDomains) > 0 || payload.EmailDomainOverride {
+		return qtx.UpdateCollege(c.Context(), db.UpdateCollegeParams{
+			AllowedEmailDomains: normalizeDomains(payload.AllowedEmailDomains),
+			EmailDomainOverride: pgtype.Bool{Bool: payload.EmailDomainOverride, Valid: true},
+			ID:                  college.ID,
+		})
+	}
+	return college, nil
+}
+
+func CreateCollege(c *fiber.Ctx) error {
+	var payload CreateCollegePayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	if strings.TrimSpace(payload.Name) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "College name is required"})
+	}
+
+	tx, err := database.DB.Begin(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create college"})
+	}
+	defer tx.Rollback(c.Context())
+	qtx := db.New(database.DB).WithTx(tx)
+
+	college, err := insertCollege(c, qtx, payload)
+	if err != nil {
+		if errors.Is(err, errDomainTaken) {
+			return domainTakenResponse(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create college"})
+	}
+
+	if err := tx.Commit(c.Context()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create college"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(collegeResponse(college))
+}
+
+func GetCollege(c *fiber.Ctx) error {
+	collegeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid college ID"})
+	}
+
+	queries := db.New(database.DB)
+
+	college, err := queries.GetCollegeByID(c.Context(), collegeID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "College not found."})
+	}
+
+	stats, err := queries.GetCollegeStats(c.Context(), collegeID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch college"})
+	}
+
+	response := collegeResponse(college)
+	response["stats"] = fiber.Map{
+		"users":  stats.UserCount,
+		"events": stats.EventCount,
+		"forums": stats.ForumCount,
+		"venues": stats.VenueCount,
+	}
+	return c.JSON(response)
+}
+
+func UpdateCollege(c *fiber.Ctx) error {
+	collegeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid college ID"})
+	}
+
+	var payload UpdateCollegePayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	queries := db.New(database.DB)
+	params := db.UpdateCollegeParams{ID: collegeID}
+
+	if payload.Name != nil {
+		name := strings.TrimSpace(*payload.Name)
+		if name == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "College name cannot be empty"})
+		}
+		params.Name = pgtype.Text{String: name, Valid: true}
+	}
+	if payload.DomainName != nil {
+		domain := normalizeDomain(*payload.DomainName)
+		if domain == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Domain name cannot be empty"})
+		}
+		if existing, err := queries.GetCollegeByDomain(c.Context(), pgtype.Text{String: domain, Valid: true}); err == nil && existing.ID != collegeID {
+			return domainTakenResponse(c)
+		}
+		params.DomainName = pgtype.Text{String: domain, Valid: true}
+	}
+	if payload.HasPaid != nil {
+		params.HasPaid = pgtype.Bool{Bool: *payload.HasPaid, Valid: true}
+	}
+	if payload.AllowedEmailDomains != nil {
+		params.AllowedEmailDomains = normalizeDomains(*payload.AllowedEmailDomains)
+	}
+	if payload.EmailDomainOverride != nil {
+		params.EmailDomainOverride = pgtype.Bool{Bool: *payload.EmailDomainOverride, Valid: true}
+	}
+	if payload.RequireStaffInCharge != nil {
+		params.RequireStaffInCharge = pgtype.Bool{Bool: *payload.RequireStaffInCharge, Valid: true}
+	}
+
+	college, err := queries.UpdateCollege(c.Context(), params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "College not found."})
+		}
+		if database.IsUniqueViolation(err) {
+			return domainTakenResponse(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update college"})
+	}
+
+	return c.JSON(collegeResponse(college))
+}
+
+// DeleteCollege removes a college and, through ON DELETE CASCADE, everything that
+// belongs to it. Unless ?force=true is passed, a college that still has users or
+// events is not deleted; the response lists what would be lost instead.
+func DeleteCollege(c *fiber.Ctx) error {
+	collegeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid college ID"})
+	}
+
+	queries := db.New(database.DB)
+
+	stats, err := queries.GetCollegeStats(c.Context(), collegeID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete college"})
+	}
+
+	if (stats.UserCount > 0 || stats.EventCount > 0) && !c.QueryBool("force") {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "This college still has users or events. Deleting it will permanently remove them. Repeat the request with ?force=true to confirm.",
+			"code":  "COLLEGE_NOT_EMPTY",
+			"stats": fiber.Map{
+				"users":  stats.UserCount,
+				"events": stats.EventCount,
+				"forums": stats.ForumCount,
+				"venues": stats.VenueCount,
+			},
+		})
+	}
+
+	rows, err := queries.DeleteCollege(c.Context(), collegeID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete college"})
+	}
+	if rows == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "College not found."})
+	}
+
+	return c.JSON(fiber.Map{"message": "College deleted successfully."})
+}
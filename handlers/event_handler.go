@@ -0,0 +1,675 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateEventPayload struct {
+	ForumID           uuid.UUID  `json:"forumId"`
+	Name              string     `json:"name"`
+	Description       string     `json:"description"`
+	StartTime         time.Time  `json:"startTime"`
+	EndTime           time.Time  `json:"endTime"`
+	VenueID           *uuid.UUID `json:"venueId"`
+	BannerImage       string     `json:"bannerImage"`
+	ResizeMode        string     `json:"resizeMode"`
+	RegistrationLink  string     `json:"registrationLink"`
+	RegistrationLimit *int32     `json:"registrationLimit"`
+}
+
+type UpdateEventPayload struct {
+	Name                   *string    `json:"name"`
+	Description            *string    `json:"description"`
+	StartTime              *time.Time `json:"startTime"`
+	EndTime                *time.Time `json:"endTime"`
+	VenueID                *uuid.UUID `json:"venueId"`
+	ClearVenue             bool       `json:"clearVenue"`
+	BannerImage            *string    `json:"bannerImage"`
+	ResizeMode             *string    `json:"resizeMode"`
+	RegistrationLink       *string    `json:"registrationLink"`
+	RegistrationLimit      *int32     `json:"registrationLimit"`
+	ClearRegistrationLimit bool       `json:"clearRegistrationLimit"`
+}
+
+type EventTransitionPayload struct {
+	Note string `json:"note"`
+}
+
+var errInvalidEventID = errors.New("invalid event ID")
+
+func optionalText(s string) pgtype.Text {
+	s = strings.TrimSpace(s)
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func textPtr(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+func int4Ptr(i pgtype.Int4) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+// eventTimestamp stores event times as UTC wall clock in the timestamp columns.
+func eventTimestamp(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}
+}
+
+func eventResponse(event db.Event) fiber.Map {
+	return fiber.Map{
+		"id":                event.ID,
+		"name":              event.Name,
+		"description":       textPtr(event.Description),
+		"startTime":         event.StartTime,
+		"endTime":           event.EndTime,
+		"status":            event.Status,
+		"bannerImage":       textPtr(event.BannerImage),
+		"resizeMode":        textPtr(event.ResizeMode),
+		"registrationLink":  textPtr(event.RegistrationLink),
+		"registrationLimit": int4Ptr(event.RegistrationLimit),
+		"collegeId":         event.CollegeID,
+		"venueId":           event.VenueID,
+		"organizerId":       event.OrganizerID,
+		"forumId":           event.ForumID,
+		"createdAt":         event.CreatedAt,
+		"updatedAt":         event.UpdatedAt,
+	}
+}
+
+// getCollegeEvent loads the event in the :id route param. Events of other
+// colleges are reported as pgx.ErrNoRows.
+func getCollegeEvent(c *fiber.Ctx, queries *db.Queries) (db.Event, error) {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return db.Event{}, errInvalidEventID
+	}
+
+	event, err := queries.GetEventByID(c.Context(), eventID)
+	if err != nil {
+		return db.Event{}, err
+	}
+	if authUser.CollegeID == nil || event.CollegeID != *authUser.CollegeID {
+		return db.Event{}, pgx.ErrNoRows
+	}
+	return event, nil
+}
+
+func eventLookupErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errInvalidEventID):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid event ID"})
+	case errors.Is(err, pgx.ErrNoRows):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Event not found"})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch event"})
+	}
+}
+
+// canViewEvent reports whether user may see event. Confirmed events are public
+// within the college; everything else is limited to the people working on it.
+func canViewEvent(ctx context.Context, queries *db.Queries, user middleware.AuthUser, event db.Event) (bool, error) {
+	if event.Status == db.EventStatusConfirmed {
+		return true, nil
+	}
+	roles, err := eventRolesOf(ctx, queries, user, event)
+	return roles != 0, err
+}
+
+// validateEventVenue checks that venueID is an active venue of the college.
+func validateEventVenue(ctx context.Context, queries *db.Queries, collegeID, venueID uuid.UUID) bool {
+	venue, err := queries.GetVenueByID(ctx, venueID)
+	if err != nil || venue.CollegeID != collegeID {
+		return false
+	}
+	return !venue.IsActive.Valid || venue.IsActive.Bool
+}
+
+// venueConflicts lists the events that already hold venueID during [start, end).
+func venueConflicts(ctx context.Context, queries *db.Queries, venueID, excludeEventID uuid.UUID, start, end time.Time) ([]db.ListVenueConflictsRow, error) {
+	return queries.ListVenueConflicts(ctx, db.ListVenueConflictsParams{
+		VenueID:        pgtype.UUID{Bytes: venueID, Valid: true},
+		ExcludeEventID: excludeEventID,
+		StartTime:      eventTimestamp(start),
+		EndTime:        eventTimestamp(end),
+	})
+}
+
+func venueConflictResponse(c *fiber.Ctx, conflicts []db.ListVenueConflictsRow) error {
+	items := make([]fiber.Map, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		items = append(items, fiber.Map{
+			"id":        conflict.ID,
+			"name":      conflict.Name,
+			"startTime": conflict.StartTime,
+			"endTime":   conflict.EndTime,
+			"status":    conflict.Status,
+			"forumId":   conflict.ForumID,
+		})
+	}
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error":     "The venue is already booked for part of this time slot.",
+		"code":      "VENUE_CONFLICT",
+		"conflicts": items,
+	})
+}
+
+// checkVenueAvailable writes a 409 listing the clashing events if venueID is
+// taken during [start, end). It returns handled=true when a response was sent.
+func checkVenueAvailable(c *fiber.Ctx, queries *db.Queries, venueID, excludeEventID uuid.UUID, start, end time.Time) (handled bool, err error) {
+	conflicts, err := venueConflicts(c.Context(), queries, venueID, excludeEventID, start, end)
+	if err != nil {
+		return true, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check venue availability"})
+	}
+	if len(conflicts) > 0 {
+		return true, venueConflictResponse(c, conflicts)
+	}
+	return false, nil
+}
+
+func invalidRegistrationLimitResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "registrationLimit must be a positive number"})
+}
+
+func invalidVenueResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": "The selected venue does not exist or is not available.",
+		"code":  "INVALID_VENUE",
+	})
+}
+
+// ListEvents lists events of the user's college. College admins see every
+// event; everyone else sees confirmed events plus those of forums they head.
+func ListEvents(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	page, limit := parsePagination(c)
+
+	queries := db.New(database.DB)
+
+	params := db.ListCollegeEventsParams{
+		CollegeID:       *authUser.CollegeID,
+		IncludeAll:      authUser.Role == string(db.UserRoleCollegeAdmin),
+		VisibleForumIds: []uuid.UUID{},
+		PageLimit:       int32(limit),
+		PageOffset:      int32((page - 1) * limit),
+	}
+
+	if authUser.Role == string(db.UserRoleForumHead) {
+		forumIDs, err := queries.ListVerifiedForumIDsForUser(c.Context(), authUser.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch events"})
+		}
+		if forumIDs != nil {
+			params.VisibleForumIds = forumIDs
+		}
+	}
+
+	if s := c.Query("status"); s != "" {
+		status := db.EventStatus(s)
+		switch status {
+		case db.EventStatusDraft, db.EventStatusPendingApproval, db.EventStatusConfirmed, db.EventStatusCancelled:
+			params.Status = db.NullEventStatus{EventStatus: status, Valid: true}
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid status"})
+		}
+	}
+	if f := c.Query("forumId"); f != "" {
+		forumID, err := uuid.Parse(f)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum ID"})
+		}
+		params.ForumID = pgtype.UUID{Bytes: forumID, Valid: true}
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "from must be an RFC 3339 timestamp"})
+		}
+		params.RangeStart = eventTimestamp(t)
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "to must be an RFC 3339 timestamp"})
+		}
+		params.RangeEnd = eventTimestamp(t)
+	}
+
+	events, err := queries.ListCollegeEvents(c.Context(), params)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch events"})
+	}
+
+	total, err := queries.CountCollegeEvents(c.Context(), db.CountCollegeEventsParams{
+		CollegeID:       params.CollegeID,
+		IncludeAll:      params.IncludeAll,
+		VisibleForumIds: params.VisibleForumIds,
+		Status:          params.Status,
+		ForumID:         params.ForumID,
+		RangeStart:      params.RangeStart,
+		RangeEnd:        params.RangeEnd,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch events"})
+	}
+
+	items := make([]fiber.Map, 0, len(events))
+	for _, event := range events {
+		items = append(items, eventResponse(event))
+	}
+
+	return c.JSON(fiber.Map{
+		"events": items,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+// GetEvent shows an event together with its status history, co-hosts, staff
+// and how full it is.
+func GetEvent(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	queries := db.New(database.DB)
+
+	event, err := getCollegeEvent(c, queries)
+	if err != nil {
+		return eventLookupErrorResponse(c, err)
+	}
+
+	visible, err := canViewEvent(c.Context(), queries, authUser, event)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch event"})
+	}
+	if !visible {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Event not found"})
+	}
+
+	transitions, err := queries.ListEventTransitions(c.Context(), event.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch event"})
+	}
+
+	collaborators, err := queries.ListEventCollaborators(c.Context(), event.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch event"})
+	}
+
+	history := make([]fiber.Map, 0, len(transitions))
+	for _, t := range transitions {
+		var from *db.EventStatus
+		if t.FromStatus.Valid {
+			from = &t.FromStatus.EventStatus
+		}
+		history = append(history, fiber.Map{
+			"from":      from,
+			"to":        t.ToStatus,
+			"actorId":   t.ActorID,
+			"actorName": textPtr(t.ActorName),
+			"note":      textPtr(t.Note),
+			"createdAt": t.CreatedAt,
+		})
+	}
+
+	coHosts := make([]fiber.Map, 0, len(collaborators))
+	for _, collaborator := range collaborators {
+		// Pending and rejected invitations are only the organizers' business.
+		if collaborator.Status != db.CollaborationStatusAccepted && authUser.Role != string(db.UserRoleCollegeAdmin) &&
+			!middleware.IsVerifiedForumHead(c.Context(), queries, authUser, event.ForumID) {
+			continue
+		}
+		coHosts = append(coHosts, collaboratorResponse(collaborator))
+	}
+
+	staff, err := queries.ListEventStaff(c.Context(), event.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch event"})
+	}
+
+	staffInCharge := make([]fiber.Map, 0, len(staff))
+	for _, assignment := range staff {
+		if assignment.Status.ApprovalStatus != db.ApprovalStatusApproved && authUser.Role != string(db.UserRoleCollegeAdmin) &&
+			!middleware.IsVerifiedForumHead(c.Context(), queries, authUser, event.ForumID) {
+			continue
+		}
+		staffInCharge = append(staffInCharge, staffAssignmentResponse(assignment))
+	}
+
+	summary, err := registrationSummary(c.Context(), queries, event, pgtype.UUID{Bytes: authUser.ID, Valid: true})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch event"})
+	}
+
+	response := eventResponse(event)
+	response["history"] = history
+	response["collaborators"] = coHosts
+	response["staff"] = staffInCharge
+	response["registration"] = summary
+	return c.JSON(response)
+}
+
+// CreateEvent creates a draft event for a forum the user is a verified head of.
+func CreateEvent(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	var payload CreateEventPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	name := strings.TrimSpace(payload.Name)
+	if name == "" || payload.ForumID == uuid.Nil || payload.StartTime.IsZero() || payload.EndTime.IsZero() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "forumId, name, startTime and endTime are required"})
+	}
+	if !payload.EndTime.After(payload.StartTime) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "endTime must be after startTime"})
+	}
+
+	var registrationLimit pgtype.Int4
+	if payload.RegistrationLimit != nil {
+		if *payload.RegistrationLimit < 1 {
+			return invalidRegistrationLimitResponse(c)
+		}
+		registrationLimit = pgtype.Int4{Int32: *payload.RegistrationLimit, Valid: true}
+	}
+
+	queries := db.New(database.DB)
+
+	if !middleware.IsVerifiedForumHead(c.Context(), queries, authUser, payload.ForumID) {
+		return middleware.Forbidden(c, "You are not a verified head of this forum.")
+	}
+	forum, err := queries.GetForumByID(c.Context(), payload.ForumID)
+	if err != nil || forum.CollegeID != *authUser.CollegeID || forum.ArchivedAt.Valid {
+		return invalidForumResponse(c)
+	}
+
+	var venueID pgtype.UUID
+	if payload.VenueID != nil {
+		if !validateEventVenue(c.Context(), queries, forum.CollegeID, *payload.VenueID) {
+			return invalidVenueResponse(c)
+		}
+		if handled, err := checkVenueAvailable(c, queries, *payload.VenueID, uuid.Nil, payload.StartTime, payload.EndTime); handled {
+			return err
+		}
+		venueID = pgtype.UUID{Bytes: *payload.VenueID, Valid: true}
+	}
+
+	tx, err := database.DB.Begin(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create event"})
+	}
+	defer tx.Rollback(c.Context())
+	qtx := queries.WithTx(tx)
+
+	event, err := qtx.CreateEvent(c.Context(), db.CreateEventParams{
+		Name:              name,
+		Description:       optionalText(payload.Description),
+		StartTime:         eventTimestamp(payload.StartTime),
+		EndTime:           eventTimestamp(payload.EndTime),
+		BannerImage:       optionalText(payload.BannerImage),
+		ResizeMode:        optionalText(payload.ResizeMode),
+		RegistrationLink:  optionalText(payload.RegistrationLink),
+		CollegeID:         forum.CollegeID,
+		VenueID:           venueID,
+		OrganizerID:       authUser.ID,
+		ForumID:           forum.ID,
+		RegistrationLimit: registrationLimit,
+	})
+	if err != nil {
+		if database.IsExclusionViolation(err) {
+			// Another booking slipped in after the check above.
+			if handled, err := checkVenueAvailable(c, queries, *payload.VenueID, uuid.Nil, payload.StartTime, payload.EndTime); handled {
+				return err
+			}
+			return venueConflictResponse(c, nil)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create event"})
+	}
+
+	err = qtx.CreateEventTransition(c.Context(), db.CreateEventTransitionParams{
+		EventID:  event.ID,
+		ToStatus: event.Status,
+		ActorID:  pgtype.UUID{Bytes: authUser.ID, Valid: true},
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create event"})
+	}
+
+	if err := tx.Commit(c.Context()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create event"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(eventResponse(event))
+}
+
+// UpdateEvent edits a draft event. Verified heads of the organizing forum may
+// edit every field; those of accepted co-hosting forums only the description,
+// banner and registration link.
+func UpdateEvent(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	queries := db.New(database.DB)
+
+	event, err := getCollegeEvent(c, queries)
+	if err != nil {
+		return eventLookupErrorResponse(c, err)
+	}
+
+	roles, err := eventRolesOf(c.Context(), queries, authUser, event)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update event"})
+	}
+	if roles&(eventRoleOrganizer|eventRoleCollaborator) == 0 {
+		return middleware.Forbidden(c, "Only verified heads of the organizing or co-hosting forums can edit this event.")
+	}
+	if event.Status != db.EventStatusDraft {
+		return eventNotEditableResponse(c, event.Status)
+	}
+
+	var payload UpdateEventPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	// Co-hosts may only touch how the event is presented, not when or where it happens.
+	if roles&eventRoleOrganizer == 0 &&
+		(payload.Name != nil || payload.StartTime != nil || payload.EndTime != nil || payload.VenueID != nil || payload.ClearVenue ||
+			payload.RegistrationLimit != nil || payload.ClearRegistrationLimit) {
+		return middleware.Forbidden(c, "Co-hosting forums can only edit the description, banner and registration link.")
+	}
+
+	params := db.UpdateEventParams{
+		ID:                     event.ID,
+		ClearVenue:             payload.ClearVenue,
+		ClearRegistrationLimit: payload.ClearRegistrationLimit,
+	}
+	if payload.Name != nil {
+		name := strings.TrimSpace(*payload.Name)
+		if name == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Event name cannot be empty"})
+		}
+		params.Name = pgtype.Text{String: name, Valid: true}
+	}
+	if payload.Description != nil {
+		params.Description = pgtype.Text{String: strings.TrimSpace(*payload.Description), Valid: true}
+	}
+	if payload.BannerImage != nil {
+		params.BannerImage = pgtype.Text{String: strings.TrimSpace(*payload.BannerImage), Valid: true}
+	}
+	if payload.ResizeMode != nil {
+		params.ResizeMode = pgtype.Text{String: strings.TrimSpace(*payload.ResizeMode), Valid: true}
+	}
+	if payload.RegistrationLink != nil {
+		params.RegistrationLink = pgtype.Text{String: strings.TrimSpace(*payload.RegistrationLink), Valid: true}
+	}
+	if payload.RegistrationLimit != nil && !payload.ClearRegistrationLimit {
+		if *payload.RegistrationLimit < 1 {
+			return invalidRegistrationLimitResponse(c)
+		}
+		params.RegistrationLimit = pgtype.Int4{Int32: *payload.RegistrationLimit, Valid: true}
+	}
+
+	startTime, endTime := event.StartTime.Time, event.EndTime.Time
+	if payload.StartTime != nil {
+		startTime = payload.StartTime.UTC()
+		params.StartTime = eventTimestamp(startTime)
+	}
+	if payload.EndTime != nil {
+		endTime = payload.EndTime.UTC()
+		params.EndTime = eventTimestamp(endTime)
+	}
+	if !endTime.After(startTime) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "endTime must be after startTime"})
+	}
+
+	venueID := event.VenueID
+	if payload.ClearVenue {
+		venueID = pgtype.UUID{}
+	} else if payload.VenueID != nil {
+		if !validateEventVenue(c.Context(), queries, event.CollegeID, *payload.VenueID) {
+			return invalidVenueResponse(c)
+		}
+		venueID = pgtype.UUID{Bytes: *payload.VenueID, Valid: true}
+		params.VenueID = venueID
+	}
+	if venueID.Valid {
+		if handled, err := checkVenueAvailable(c, queries, venueID.Bytes, event.ID, startTime, endTime); handled {
+			return err
+		}
+	}
+
+	updated, err := queries.UpdateEvent(c.Context(), params)
+	if err != nil {
+		// The update only matches drafts, so no rows means the event moved on since it was read.
+		if errors.Is(err, pgx.ErrNoRows) {
+			current, err := queries.GetEventByID(c.Context(), event.ID)
+			if err != nil {
+				return eventLookupErrorResponse(c, err)
+			}
+			return eventNotEditableResponse(c, current.Status)
+		}
+		if database.IsExclusionViolation(err) && venueID.Valid {
+			if handled, err := checkVenueAvailable(c, queries, venueID.Bytes, event.ID, startTime, endTime); handled {
+				return err
+			}
+			return venueConflictResponse(c, nil)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update event"})
+	}
+
+	return c.JSON(eventResponse(updated))
+}
+
+func eventNotEditableResponse(c *fiber.Ctx, status db.EventStatus) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error":  "Only draft events can be edited.",
+		"code":   "EVENT_NOT_EDITABLE",
+		"status": status,
+	})
+}
+
+// transitionEvent returns a handler that moves an event to status to, after
+// checking the transition is allowed and the user may make it.
+func transitionEvent(to db.EventStatus) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+		queries := db.New(database.DB)
+
+		var payload EventTransitionPayload
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&payload); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+			}
+		}
+
+		event, err := getCollegeEvent(c, queries)
+		if err != nil {
+			return eventLookupErrorResponse(c, err)
+		}
+
+		roles, err := eventRolesOf(c.Context(), queries, authUser, event)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update event"})
+		}
+
+		if err := checkEventTransition(roles, event.Status, to); err != nil {
+			if errors.Is(err, errTransitionDenied) {
+				return middleware.Forbidden(c, "You are not allowed to make this change to the event.")
+			}
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "The event cannot move from " + string(event.Status) + " to " + string(to) + ".",
+				"code":  "INVALID_TRANSITION",
+				"from":  event.Status,
+				"to":    to,
+			})
+		}
+
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update event"})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
+
+		if to == db.EventStatusConfirmed {
+			// Lock the event so that RemoveStaff cannot take away the last staff
+			// in charge between this check and the status change.
+			if _, err := qtx.LockEventStatus(c.Context(), event.ID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update event"})
+			}
+			ready, err := hasRequiredStaff(c.Context(), qtx, event)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update event"})
+			}
+			if !ready {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "At least one staff in charge must accept the event before it can be confirmed.",
+					"code":  "STAFF_REQUIRED",
+				})
+			}
+		}
+
+		updated, err := applyEventTransition(c.Context(), qtx, event, to,
+			pgtype.UUID{Bytes: authUser.ID, Valid: true}, strings.TrimSpace(payload.Note))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "The event was changed by someone else. Please reload and try again.",
+					"code":  "EVENT_CHANGED",
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update event"})
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update event"})
+		}
+
+		return c.JSON(eventResponse(updated))
+	}
+}
+
+var (
+	SubmitEvent   = transitionEvent(db.EventStatusPendingApproval)
+	WithdrawEvent = transitionEvent(db.EventStatusDraft)
+	ConfirmEvent  = transitionEvent(db.EventStatusConfirmed)
+	CancelEvent   = transitionEvent(db.EventStatusCancelled)
+)
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// eventRole is the capacity in which a user acts on an event.
+type eventRole int
+
+const (
+	// eventRoleOrganizer is a verified head of the organizing forum.
+	eventRoleOrganizer eventRole = 1 << iota
+	// eventRoleReviewer is a college admin of the event's college or a staff
+	// member whose assignment to the event has been approved.
+	eventRoleReviewer
+	// eventRoleCollaborator is a verified head of a forum that accepted to
+	// co-host the event.
+	eventRoleCollaborator
+)
+
+type eventTransition struct {
+	from db.EventStatus
+	to   db.EventStatus
+}
+
+// eventTransitions lists every allowed status change and who may make it.
+// Anything not listed here is rejected.
+var eventTransitions = map[eventTransition]eventRole{
+	{db.EventStatusDraft, db.EventStatusPendingApproval}:     eventRoleOrganizer,
+	{db.EventStatusDraft, db.EventStatusCancelled}:           eventRoleOrganizer,
+	{db.EventStatusPendingApproval, db.EventStatusDraft}:     eventRoleOrganizer | eventRoleReviewer,
+	{db.EventStatusPendingApproval, db.EventStatusConfirmed}: eventRoleReviewer,
+	{db.EventStatusPendingApproval, db.EventStatusCancelled}: eventRoleOrganizer | eventRoleReviewer,
+	{db.EventStatusConfirmed, db.EventStatusCancelled}:       eventRoleReviewer,
+}
+
+var (
+	errInvalidTransition = errors.New("event status transition is not allowed")
+	errTransitionDenied  = errors.New("user may not make this event status transition")
+)
+
+// eventRolesOf works out in which capacities user may act on event.
+func eventRolesOf(ctx context.Context, queries *db.Queries, user middleware.AuthUser, event db.Event) (eventRole, error) {
+	var roles eventRole
+	if user.CollegeID == nil || *user.CollegeID != event.CollegeID {
+		return roles, nil
+	}
+
+	if middleware.IsVerifiedForumHead(ctx, queries, user, event.ForumID) {
+		roles |= eventRoleOrganizer
+	}
+
+	if user.Role == string(db.UserRoleForumHead) && roles&eventRoleOrganizer == 0 {
+		isCollaborator, err := queries.IsAcceptedCollaborator(ctx, db.IsAcceptedCollaboratorParams{
+			EventID: event.ID,
+			UserID:  user.ID,
+		})
+		if err != nil {
+			return roles, err
+		}
+		if isCollaborator {
+			roles |= eventRoleCollaborator
+		}
+	}
+
+	if user.Role == string(db.UserRoleCollegeAdmin) {
+		roles |= eventRoleReviewer
+	} else {
+		isStaff, err := queries.IsApprovedEventStaff(ctx, db.IsApprovedEventStaffParams{
+			EventID: event.ID,
+			UserID:  user.ID,
+		})
+		if err != nil {
+			return roles, err
+		}
+		if isStaff {
+			roles |= eventRoleReviewer
+		}
+	}
+
+	return roles, nil
+}
+
+// checkEventTransition reports whether a user acting with roles may move an
+// event from one status to another.
+func checkEventTransition(roles eventRole, from, to db.EventStatus) error {
+	allowed, ok := eventTransitions[eventTransition{from, to}]
+	if !ok {
+		return errInvalidTransition
+	}
+	if roles&allowed == 0 {
+		return errTransitionDenied
+	}
+	return nil
+}
+
+// applyEventTransition moves event to status to and records who did it.
+// qtx must be bound to a transaction. It returns pgx.ErrNoRows if the event
+// changed status concurrently.
+func applyEventTransition(ctx context.Context, qtx *db.Queries, event db.Event, to db.EventStatus, actorID pgtype.UUID, note string) (db.Event, error) {
+	updated, err := qtx.SetEventStatus(ctx, db.SetEventStatusParams{
+		ToStatus:   to,
+		ID:         event.ID,
+		FromStatus: event.Status,
+	})
+	if err != nil {
+		return db.Event{}, err
+	}
+
+	err = qtx.CreateEventTransition(ctx, db.CreateEventTransitionParams{
+		EventID:    event.ID,
+		FromStatus: db.NullEventStatus{EventStatus: event.Status, Valid: true},
+		ToStatus:   to,
+		ActorID:    actorID,
+		Note:       pgtype.Text{String: note, Valid: note != ""},
+	})
+	if err != nil {
+		return db.Event{}, err
+	}
+
+	return updated, nil
+}
+
+// hasRequiredStaff reports whether event satisfies its college's staff policy:
+// colleges that require a staff in charge only confirm events with at least
+// one approved staff assignment.
+func hasRequiredStaff(ctx context.Context, queries *db.Queries, event db.Event) (bool, error) {
+	college, err := queries.GetCollegeByID(ctx, event.CollegeID)
+	if err != nil {
+		return false, err
+	}
+	if !college.RequireStaffInCharge {
+		return true, nil
+	}
+
+	approved, err := queries.CountApprovedEventStaff(ctx, event.ID)
+	if err != nil {
+		return false, err
+	}
+	return approved > 0, nil
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateForumPayload struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type UpdateForumPayload struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+func forumResponse(forum db.Forum, heads []fiber.Map) fiber.Map {
+	var description *string
+	if forum.Description.Valid {
+		description = &forum.Description.String
+	}
+	if heads == nil {
+		heads = []fiber.Map{}
+	}
+
+	return fiber.Map{
+		"id":          forum.ID,
+		"name":        forum.Name,
+		"description": description,
+		"collegeId":   forum.CollegeID,
+		"isArchived":  forum.ArchivedAt.Valid,
+		"archivedAt":  forum.ArchivedAt,
+		"createdAt":   forum.CreatedAt,
+		"heads":       heads,
+	}
+}
+
+// verifiedHeadsByForum loads the verified heads of the given forums, keyed by forum ID.
+func verifiedHeadsByForum(ctx context.Context, queries *db.Queries, forumIDs []uuid.UUID) (map[uuid.UUID][]fiber.Map, error) {
+	heads, err := queries.ListVerifiedForumHeads(ctx, forumIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byForum := make(map[uuid.UUID][]fiber.Map, len(forumIDs))
+	for _, head := range heads {
+		byForum[head.ForumID] = append(byForum[head.ForumID], fiber.Map{
+			"userId":   head.UserID,
+			"fullName": head.FullName,
+			"email":    head.Email,
+		})
+	}
+	return byForum, nil
+}
+
+// ListForums is the forum directory of the user's college. College admins can
+// include archived forums with ?includeArchived=true.
+func ListForums(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	page, limit := parsePagination(c)
+	search := strings.TrimSpace(c.Query("search"))
+	includeArchived := authUser.Role == string(db.UserRoleCollegeAdmin) && c.QueryBool("includeArchived")
+
+	queries := db.New(database.DB)
+
+	forums, err := queries.ListCollegeForums(c.Context(), db.ListCollegeForumsParams{
+		CollegeID:       *authUser.CollegeID,
+		IncludeArchived: includeArchived,
+		Search:          search,
+		PageLimit:       int32(limit),
+		PageOffset:      int32((page - 1) * limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch forums"})
+	}
+
+	total, err := queries.CountCollegeForums(c.Context(), db.CountCollegeForumsParams{
+		CollegeID:       *authUser.CollegeID,
+		IncludeArchived: includeArchived,
+		Search:          search,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch forums"})
+	}
+
+	forumIDs := make([]uuid.UUID, 0, len(forums))
+	for _, forum := range forums {
+		forumIDs = append(forumIDs, forum.ID)
+	}
+	heads, err := verifiedHeadsByForum(c.Context(), queries, forumIDs)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch forums"})
+	}
+
+	items := make([]fiber.Map, 0, len(forums))
+	for _, forum := range forums {
+		items = append(items, forumResponse(forum, heads[forum.ID]))
+	}
+
+	return c.JSON(fiber.Map{
+		"forums": items,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+// GetForum shows a forum of the user's college with its verified heads.
+// Archived forums are only visible to college admins.
+func GetForum(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum ID"})
+	}
+
+	queries := db.New(database.DB)
+
+	forum, err := queries.GetForumByID(c.Context(), forumID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch forum"})
+	}
+	if err != nil || forum.CollegeID != *authUser.CollegeID ||
+		(forum.ArchivedAt.Valid && authUser.Role != string(db.UserRoleCollegeAdmin)) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Forum not found"})
+	}
+
+	heads, err := verifiedHeadsByForum(c.Context(), queries, []uuid.UUID{forum.ID})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch forum"})
+	}
+
+	return c.JSON(forumResponse(forum, heads[forum.ID]))
+}
+
+// ListPublicForums lists the active forums of a college by name only, so that
+// forum heads can pick their forum while registering.
+func ListPublicForums(c *fiber.Ctx) error {
+	collegeID, err := uuid.Parse(c.Params("collegeId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid college ID"})
+	}
+
+	forums, err := db.New(database.DB).ListCollegeForums(c.Context(), db.ListCollegeForumsParams{
+		CollegeID:  collegeID,
+		Search:     strings.TrimSpace(c.Query("search")),
+		PageLimit:  maxPageLimit,
+		PageOffset: 0,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch forums"})
+	}
+
+	items := make([]fiber.Map, 0, len(forums))
+	for _, forum := range forums {
+		items = append(items, fiber.Map{"id": forum.ID, "name": forum.Name})
+	}
+
+	return c.JSON(fiber.Map{"forums": items})
+}
+
+func CreateForum(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	var payload CreateForumPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	name := strings.TrimSpace(payload.Name)
+	if name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Forum name is required"})
+	}
+	description := strings.TrimSpace(payload.Description)
+
+	forum, err := db.New(database.DB).CreateForum(c.Context(), db.CreateForumParams{
+		Name:        name,
+		Description: pgtype.Text{String: description, Valid: description != ""},
+		CollegeID:   *authUser.CollegeID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create forum"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(forumResponse(forum, nil))
+}
+
+func UpdateForum(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum ID"})
+	}
+
+	var payload UpdateForumPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	params := db.UpdateForumParams{ID: forumID, CollegeID: *authUser.CollegeID}
+	if payload.Name != nil {
+		name := strings.TrimSpace(*payload.Name)
+		if name == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Forum name cannot be empty"})
+		}
+		params.Name = pgtype.Text{String: name, Valid: true}
+	}
+	if payload.Description != nil {
+		params.Description = pgtype.Text{String: strings.TrimSpace(*payload.Description), Valid: true}
+	}
+
+	forum, err := db.New(database.DB).UpdateForum(c.Context(), params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Forum not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update forum"})
+	}
+
+	return c.JSON(forumResponse(forum, nil))
+}
+
+func setForumArchived(c *fiber.Ctx, archived bool) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum ID"})
+	}
+
+	forum, err := db.New(database.DB).SetForumArchivedAt(c.Context(), db.SetForumArchivedAtParams{
+		ArchivedAt: pgtype.Timestamp{Time: time.Now(), Valid: archived},
+		ID:         forumID,
+		CollegeID:  *authUser.CollegeID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Forum not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update forum"})
+	}
+
+	return c.JSON(forumResponse(forum, nil))
+}
+
+// ArchiveForum hides a forum from the directory and registration without
+// deleting its events.
+func ArchiveForum(c *fiber.Ctx) error {
+	return setForumArchived(c, true)
+}
+
+func UnarchiveForum(c *fiber.Ctx) error {
+	return setForumArchived(c, false)
+}
+
+func DeleteForum(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	forumID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum ID"})
+	}
+
+	queries := db.New(database.DB)
+
+	// Check ownership first so the event count of other colleges' forums
+	// is never revealed.
+	forum, err := queries.GetForumByID(c.Context(), forumID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete forum"})
+	}
+	if err != nil || forum.CollegeID != *authUser.CollegeID {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Forum not found"})
+	}
+
+	eventCount, err := queries.CountForumEvents(c.Context(), forum.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete forum"})
+	}
+
+	if eventCount > 0 && !c.QueryBool("force") {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":  "This forum still has events. Deleting it will permanently remove them. Archive it instead, or repeat the request with ?force=true to confirm.",
+			"code":   "FORUM_NOT_EMPTY",
+			"events": eventCount,
+		})
+	}
+
+	rows, err := queries.DeleteForum(c.Context(), db.DeleteForumParams{
+		ID:        forum.ID,
+		CollegeID: *authUser.CollegeID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete forum"})
+	}
+	if rows == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Forum not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Forum deleted successfully."})
+}
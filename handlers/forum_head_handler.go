@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ReassignForumHeadPayload struct {
+	ForumID uuid.UUID `json:"forumId"`
+}
+
+func forumHeadResponse(forumHead db.ForumHead) fiber.Map {
+	return fiber.Map{
+		"userId":     forumHead.UserID,
+		"forumId":    forumHead.ForumID,
+		"isVerified": forumHead.IsVerified,
+	}
+}
+
+// parseForumHeadParams reads the :forumId and :userId route params.
+func parseForumHeadParams(c *fiber.Ctx) (forumID, userID uuid.UUID, err error) {
+	if forumID, err = uuid.Parse(c.Params("forumId")); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if userID, err = uuid.Parse(c.Params("userId")); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return forumID, userID, nil
+}
+
+// ListForumHeads lists the forum heads of the admin's college. It can be
+// narrowed with ?forumId= and ?verified=true|false.
+func ListForumHeads(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	page, limit := parsePagination(c)
+
+	var forumID pgtype.UUID
+	if f := c.Query("forumId"); f != "" {
+		id, err := uuid.Parse(f)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum ID"})
+		}
+		forumID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	var isVerified pgtype.Bool
+	if v := c.Query("verified"); v != "" {
+		verified, err := strconv.ParseBool(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "verified must be true or false"})
+		}
+		isVerified = pgtype.Bool{Bool: verified, Valid: true}
+	}
+
+	queries := db.New(database.DB)
+
+	forumHeads, err := queries.ListCollegeForumHeads(c.Context(), db.ListCollegeForumHeadsParams{
+		CollegeID:  *authUser.CollegeID,
+		ForumID:    forumID,
+		IsVerified: isVerified,
+		PageLimit:  int32(limit),
+		PageOffset: int32((page - 1) * limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch forum heads"})
+	}
+
+	total, err := queries.CountCollegeForumHeads(c.Context(), db.CountCollegeForumHeadsParams{
+		CollegeID:  *authUser.CollegeID,
+		ForumID:    forumID,
+		IsVerified: isVerified,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch forum heads"})
+	}
+
+	items := make([]fiber.Map, 0, len(forumHeads))
+	for _, forumHead := range forumHeads {
+		items = append(items, fiber.Map{
+			"userId":         forumHead.UserID,
+			"fullName":       forumHead.FullName,
+			"email":          forumHead.Email,
+			"approvalStatus": forumHead.ApprovalStatus,
+			"forumId":        forumHead.ForumID,
+			"forumName":      forumHead.ForumName,
+			"isVerified":     forumHead.IsVerified,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"forumHeads": items,
+		"total":      total,
+		"page":       page,
+		"limit":      limit,
+	})
+}
+
+func setForumHeadVerified(c *fiber.Ctx, verified bool) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	forumID, userID, err := parseForumHeadParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum or user ID"})
+	}
+
+	forumHead, err := db.New(database.DB).SetForumHeadVerified(c.Context(), db.SetForumHeadVerifiedParams{
+		IsVerified: verified,
+		CollegeID:  *authUser.CollegeID,
+		UserID:     userID,
+		ForumID:    forumID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Forum head not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update forum head"})
+	}
+
+	return c.JSON(forumHeadResponse(forumHead))
+}
+
+func VerifyForumHead(c *fiber.Ctx) error {
+	return setForumHeadVerified(c, true)
+}
+
+func RevokeForumHead(c *fiber.Ctx) error {
+	return setForumHeadVerified(c, false)
+}
+
+// ReassignForumHead moves a forum head to another forum of the same college.
+func ReassignForumHead(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	forumID, userID, err := parseForumHeadParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum or user ID"})
+	}
+
+	var payload ReassignForumHeadPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+	if payload.ForumID == uuid.Nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "forumId is required"})
+	}
+
+	queries := db.New(database.DB)
+
+	forum, err := queries.GetForumByID(c.Context(), forumID)
+	if err != nil || forum.CollegeID != *authUser.CollegeID {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Forum head not found"})
+	}
+	newForum, err := queries.GetForumByID(c.Context(), payload.ForumID)
+	if err != nil || newForum.CollegeID != *authUser.CollegeID {
+		return invalidForumResponse(c)
+	}
+
+	forumHead, err := queries.ReassignForumHead(c.Context(), db.ReassignForumHeadParams{
+		NewForumID: newForum.ID,
+		UserID:     userID,
+		ForumID:    forum.ID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Forum head not found"})
+		}
+		if database.IsUniqueViolation(err) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "This user is already a head of the selected forum.",
+				"code":  "ALREADY_FORUM_HEAD",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to reassign forum head"})
+	}
+
+	return c.JSON(forumHeadResponse(forumHead))
+}
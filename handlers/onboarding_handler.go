@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+	"unibook-go/util"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type OnboardAdminPayload struct {
+	FullName string `json:"fullName"`
+	Email    string `json:"email"`
+}
+
+type OnboardCollegePayload struct {
+	College CreateCollegePayload `json:"college"`
+	Admin   OnboardAdminPayload  `json:"admin"`
+}
+
+type SetPasswordPayload struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// OnboardCollege creates a college together with its first college_admin and
+// emails that admin a link to set their password.
+func OnboardCollege(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+
+		var payload OnboardCollegePayload
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+		}
+
+		payload.Admin.FullName = strings.TrimSpace(payload.Admin.FullName)
+		payload.Admin.Email = strings.TrimSpace(payload.Admin.Email)
+		if strings.TrimSpace(payload.College.Name) == "" || payload.Admin.FullName == "" || payload.Admin.Email == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "College name, admin name and admin email are required"})
+		}
+
+		queries := db.New(database.DB)
+
+		if _, err := queries.GetUserByEmail(c.Context(), payload.Admin.Email); err == nil {
+			return emailTakenResponse(c)
+		}
+
+		// The admin never learns this password; it only exists so the account has a
+		// hash to bind the invitation ticket to until they pick their own.
+		placeholder, err := util.GenerateSecureToken(32)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to onboard college"})
+		}
+		placeholderHash, err := bcrypt.GenerateFromPassword([]byte(placeholder), 10)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to onboard college"})
+		}
+
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to onboard college"})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
+
+		college, err := insertCollege(c, qtx, payload.College)
+		if err != nil {
+			if errors.Is(err, errDomainTaken) {
+				return domainTakenResponse(c)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to onboard college"})
+		}
+
+		if allowedDomains := collegeEmailDomains(college); len(allowedDomains) > 0 {
+			domain, ok := util.EmailDomain(payload.Admin.Email)
+			if !ok || !util.DomainAllowed(domain, allowedDomains) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":          "The admin email must belong to the college's domain.",
+					"code":           "EMAIL_DOMAIN_MISMATCH",
+					"allowedDomains": allowedDomains,
+				})
+			}
+		}
+
+		admin, err := qtx.CreateUser(c.Context(), db.CreateUserParams{
+			FullName:       payload.Admin.FullName,
+			Email:          payload.Admin.Email,
+			PasswordHash:   string(placeholderHash),
+			Role:           db.UserRoleCollegeAdmin,
+			CollegeID:      college.ID,
+			ApprovalStatus: db.ApprovalStatusApproved,
+		})
+		if err != nil {
+			if database.IsUniqueViolation(err) {
+				return emailTakenResponse(c)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to onboard college"})
+		}
+
+		if err := qtx.CreateUserInvitation(c.Context(), db.CreateUserInvitationParams{
+			UserID:    admin.ID,
+			InvitedBy: pgtype.UUID{Bytes: authUser.ID, Valid: true},
+		}); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to onboard college"})
+		}
+
+		college, err = qtx.MarkCollegeOnboarded(c.Context(), db.MarkCollegeOnboardedParams{
+			ID:          college.ID,
+			OnboardedBy: pgtype.UUID{Bytes: authUser.ID, Valid: true},
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to onboard college"})
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to onboard college"})
+		}
+
+		adminResponse := fiber.Map{
+			"id":       admin.ID,
+			"fullName": admin.FullName,
+			"email":    admin.Email,
+			"role":     admin.Role,
+		}
+
+		if err := sendAdminInvitation(cfg, admin, admin.PasswordHash, college.Name); err != nil {
+			log.Printf("Failed to send invitation to %s: %v", admin.Email, err)
+			return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+				"message":        "College onboarded, but the invitation could not be sent. Resend it to the college admin.",
+				"code":           "INVITATION_NOT_SENT",
+				"invitationSent": false,
+				"college":        collegeResponse(college),
+				"admin":          adminResponse,
+			})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"message":        "College onboarded. An invitation has been sent to the college admin.",
+			"invitationSent": true,
+			"college":        collegeResponse(college),
+			"admin":          adminResponse,
+		})
+	}
+}
+
+// sendAdminInvitation emails admin a set-password link bound to passwordHash.
+// It waits for the mail server so that callers can report a failed send.
+func sendAdminInvitation(cfg *config.Config, admin db.User, passwordHash string, collegeName string) error {
+	ticket, err := util.GenerateInviteTicket(cfg, admin.ID, passwordHash)
+	if err != nil {
+		return err
+	}
+	link := cfg.AppURL + "/set-password?token=" + url.QueryEscape(ticket)
+	return util.SendInvitationEmail(cfg, admin.Email, admin.FullName, collegeName, link)
+}
+
+// ResendAdminInvite sends a fresh invitation to a college admin who has not set
+// their password yet. The placeholder password is replaced first, so links
+// from earlier invitations stop working.
+func ResendAdminInvite(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+
+		collegeID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid college ID"})
+		}
+		userID, err := uuid.Parse(c.Params("userId"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user ID"})
+		}
+
+		queries := db.New(database.DB)
+
+		college, err := queries.GetCollegeByID(c.Context(), collegeID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "College not found."})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resend invitation"})
+		}
+
+		admin, err := queries.GetInvitedCollegeAdmin(c.Context(), db.GetInvitedCollegeAdminParams{
+			UserID:    userID,
+			CollegeID: college.ID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "No pending invitation for this college admin."})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resend invitation"})
+		}
+
+		placeholder, err := util.GenerateSecureToken(32)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resend invitation"})
+		}
+		placeholderHash, err := bcrypt.GenerateFromPassword([]byte(placeholder), 10)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resend invitation"})
+		}
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resend invitation"})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
+
+		rows, err := qtx.ResetUserPassword(c.Context(), db.ResetUserPasswordParams{
+			NewHash: string(placeholderHash),
+			ID:      admin.ID,
+			OldHash: admin.PasswordHash,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resend invitation"})
+		}
+		if rows == 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "The college admin has already set their password.",
+				"code":  "INVITATION_ACCEPTED",
+			})
+		}
+
+		if err := qtx.RenewUserInvitation(c.Context(), db.RenewUserInvitationParams{
+			UserID:    admin.ID,
+			InvitedBy: pgtype.UUID{Bytes: authUser.ID, Valid: true},
+		}); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resend invitation"})
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resend invitation"})
+		}
+
+		// Earlier links stopped working with the commit, so a failed send is
+		// reported and can simply be retried.
+		if err := sendAdminInvitation(cfg, admin, string(placeholderHash), college.Name); err != nil {
+			log.Printf("Failed to send invitation to %s: %v", admin.Email, err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error":          "The invitation could not be sent. Please try again.",
+				"code":           "INVITATION_NOT_SENT",
+				"invitationSent": false,
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"message":        "A new invitation has been sent to the college admin.",
+			"invitationSent": true,
+		})
+	}
+}
+
+// SetPassword completes an invitation: it sets the invited user's password and
+// marks their email as verified, since the link could only be opened from it.
+func SetPassword(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var payload SetPasswordPayload
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+		}
+
+		if payload.Token == "" || payload.Password == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "payload is Required"})
+		}
+
+		userID, fingerprint, err := util.ParseInviteTicket(cfg, payload.Token)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired invitation"})
+		}
+
+		queries := db.New(database.DB)
+
+		oldHash, err := queries.GetUserPasswordHash(c.Context(), userID)
+		if err != nil || util.HashToken(oldHash) != fingerprint {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired invitation"})
+		}
+
+		newPassword, err := bcrypt.GenerateFromPassword([]byte(payload.Password), 10)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to set password"})
+		}
+
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to set password"})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
+
+		rows, err := qtx.ResetUserPassword(c.Context(), db.ResetUserPasswordParams{
+			NewHash: string(newPassword),
+			ID:      userID,
+			OldHash: oldHash,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to set password"})
+		}
+		if rows == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired invitation"})
+		}
+
+		if _, err := qtx.VerifyUserEmail(c.Context(), userID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to set password"})
+		}
+
+		if err := qtx.DeleteUserInvitation(c.Context(), userID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to set password"})
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to set password"})
+		}
+
+		return c.JSON(fiber.Map{
+			"message": "Password set successfully. You can now log in.",
+		})
+	}
+}
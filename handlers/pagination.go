@@ -0,0 +1,24 @@
+package handlers
+
+import "github.com/gofiber/fiber/v2"
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// parsePagination reads the ?page= and ?limit= query params, clamping them to sane values.
+func parsePagination(c *fiber.Ctx) (page, limit int) {
+	page = c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	limit = c.QueryInt("limit", defaultPageLimit)
+	if limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return page, limit
+}
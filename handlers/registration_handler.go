@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+	"unibook-go/registration"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// registrationSummary describes how full event is and where userID stands:
+// registered, waiting, or holding a spot offered from the waitlist.
+func registrationSummary(ctx context.Context, queries *db.Queries, event db.Event, userID pgtype.UUID) (fiber.Map, error) {
+	var venueCapacity pgtype.Int4
+	if event.VenueID.Valid {
+		venue, err := queries.GetVenueByID(ctx, event.VenueID.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		venueCapacity = pgtype.Int4{Int32: venue.Capacity, Valid: true}
+	}
+
+	count, err := queries.CountEventRegistrations(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	offered, err := queries.CountActiveWaitlistOffers(ctx, db.CountActiveWaitlistOffersParams{
+		EventID: event.ID,
+		Now:     registration.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	waiting, err := queries.CountWaitingEntries(ctx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	isRegistered := false
+	var waitlist fiber.Map
+	if userID.Valid {
+		_, err = queries.GetEventRegistration(ctx, db.GetEventRegistrationParams{EventID: event.ID, UserID: userID.Bytes})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		isRegistered = err == nil
+
+		entry, err := queries.GetWaitlistEntry(ctx, db.GetWaitlistEntryParams{EventID: event.ID, UserID: userID.Bytes})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		if err == nil {
+			waitlist, err = waitlistEntryResponse(ctx, queries, entry)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	capacity := registration.Capacity(event.RegistrationLimit, venueCapacity)
+	var spotsLeft *int64
+	if capacity != nil {
+		left := max(int64(*capacity)-count-offered, 0)
+		spotsLeft = &left
+	}
+
+	return fiber.Map{
+		"capacity":        capacity,
+		"registeredCount": count,
+		"spotsLeft":       spotsLeft,
+		"waitlistCount":   waiting,
+		"isRegistered":    isRegistered,
+		"waitlist":        waitlist,
+		"isOpen":          registrationOpen(event.Status, event.StartTime),
+	}, nil
+}
+
+// registrationOpen reports whether an event still takes registrations: it must
+// be confirmed and not have started yet.
+func registrationOpen(status db.EventStatus, startTime pgtype.Timestamp) bool {
+	return status == db.EventStatusConfirmed && time.Now().Before(startTime.Time)
+}
+
+func registrationClosedResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error": "Registration is only open for confirmed events that have not started yet.",
+		"code":  "REGISTRATION_CLOSED",
+	})
+}
+
+func registrationResponse(r db.EventRegistration) fiber.Map {
+	return fiber.Map{
+		"id":        r.ID,
+		"eventId":   r.EventID,
+		"userId":    r.UserID,
+		"createdAt": r.CreatedAt,
+	}
+}
+
+// RegisterForEvent registers the user for a confirmed event. The event row is
+// locked while the spots are counted, so concurrent requests cannot overbook
+// it. Someone holding a spot offered from the waitlist claims it here.
+func RegisterForEvent(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	queries := db.New(database.DB)
+
+	event, err := getCollegeEvent(c, queries)
+	if err != nil {
+		return eventLookupErrorResponse(c, err)
+	}
+	visible, err := canViewEvent(c.Context(), queries, authUser, event)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+	}
+	if !visible {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Event not found"})
+	}
+
+	tx, err := database.DB.Begin(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+	}
+	defer tx.Rollback(c.Context())
+	qtx := queries.WithTx(tx)
+
+	locked, err := qtx.LockEventForRegistration(c.Context(), event.ID)
+	if err != nil {
+		return eventLookupErrorResponse(c, err)
+	}
+	if !registrationOpen(locked.Status, locked.StartTime) {
+		return registrationClosedResponse(c)
+	}
+
+	_, err = qtx.GetEventRegistration(c.Context(), db.GetEventRegistrationParams{EventID: event.ID, UserID: authUser.ID})
+	if err == nil {
+		return alreadyRegisteredResponse(c)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+	}
+
+	now := registration.Now()
+	entry, err := qtx.GetWaitlistEntry(c.Context(), db.GetWaitlistEntryParams{EventID: event.ID, UserID: authUser.ID})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+	}
+	claiming := err == nil && entry.OfferExpiresAt.Valid && entry.OfferExpiresAt.Time.After(now.Time)
+
+	capacity := registration.Capacity(locked.RegistrationLimit, locked.VenueCapacity)
+	if capacity != nil && !claiming {
+		registered, err := qtx.CountEventRegistrations(c.Context(), event.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+		}
+		offered, err := qtx.CountActiveWaitlistOffers(c.Context(), db.CountActiveWaitlistOffersParams{EventID: event.ID, Now: now})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+		}
+		waiting, err := qtx.CountWaitingEntries(c.Context(), event.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+		}
+		// Free spots go to the people already waiting before anyone new.
+		if registered+offered >= int64(*capacity) || waiting > 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":    "This event is full. You can join the waitlist instead.",
+				"code":     "EVENT_FULL",
+				"capacity": *capacity,
+			})
+		}
+	}
+
+	created, err := qtx.CreateEventRegistration(c.Context(), db.CreateEventRegistrationParams{
+		EventID: event.ID,
+		UserID:  authUser.ID,
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return alreadyRegisteredResponse(c)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+	}
+
+	_, err = qtx.DeleteWaitlistEntry(c.Context(), db.DeleteWaitlistEntryParams{EventID: event.ID, UserID: authUser.ID})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+	}
+
+	if err := tx.Commit(c.Context()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to register for event"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(registrationResponse(created))
+}
+
+func alreadyRegisteredResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error": "You are already registered for this event.",
+		"code":  "ALREADY_REGISTERED",
+	})
+}
+
+// CancelRegistration gives up the user's spot, which is offered to the next
+// person on the waitlist. This is only possible before the event starts.
+func CancelRegistration(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+		queries := db.New(database.DB)
+
+		event, err := getCollegeEvent(c, queries)
+		if err != nil {
+			return eventLookupErrorResponse(c, err)
+		}
+		if !time.Now().Before(event.StartTime.Time) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "Registrations cannot be cancelled once the event has started.",
+				"code":  "REGISTRATION_CLOSED",
+			})
+		}
+
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to cancel registration"})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
+
+		rows, err := qtx.DeleteEventRegistration(c.Context(), db.DeleteEventRegistrationParams{
+			EventID: event.ID,
+			UserID:  authUser.ID,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to cancel registration"})
+		}
+		if rows == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "You are not registered for this event"})
+		}
+
+		offers, err := registration.Promote(c.Context(), qtx, cfg, event.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to cancel registration"})
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to cancel registration"})
+		}
+		go registration.Notify(cfg, offers)
+
+		return c.JSON(fiber.Map{"message": "Registration cancelled successfully."})
+	}
+}
+
+// getManagedEvent loads the :id event for listing its registrants, which is
+// limited to the people running it. It returns handled=true when a response
+// was sent.
+func getManagedEvent(c *fiber.Ctx, queries *db.Queries) (event db.Event, handled bool, err error) {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	event, err = getCollegeEvent(c, queries)
+	if err != nil {
+		return event, true, eventLookupErrorResponse(c, err)
+	}
+
+	roles, err := eventRolesOf(c.Context(), queries, authUser, event)
+	if err != nil {
+		return event, true, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch registrations"})
+	}
+	if roles == 0 {
+		return event, true, middleware.Forbidden(c, "Only the organizers, co-hosts, staff and college admins can see registrations.")
+	}
+	return event, false, nil
+}
+
+// ListRegistrations pages through an event's registrants.
+func ListRegistrations(c *fiber.Ctx) error {
+	queries := db.New(database.DB)
+	event, handled, err := getManagedEvent(c, queries)
+	if handled {
+		return err
+	}
+	page, limit := parsePagination(c)
+
+	registrations, err := queries.ListEventRegistrations(c.Context(), db.ListEventRegistrationsParams{
+		EventID:    event.ID,
+		PageLimit:  int32(limit),
+		PageOffset: int32((page - 1) * limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch registrations"})
+	}
+
+	total, err := queries.CountEventRegistrations(c.Context(), event.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to count registrations"})
+	}
+
+	items := make([]fiber.Map, 0, len(registrations))
+	for _, r := range registrations {
+		items = append(items, fiber.Map{
+			"id":           r.ID,
+			"userId":       r.UserID,
+			"fullName":     r.FullName,
+			"email":        r.Email,
+			"role":         r.Role,
+			"registeredAt": r.CreatedAt,
+			"checkedInAt":  r.CheckedInAt,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"registrations": items,
+		"total":         total,
+		"page":          page,
+		"limit":         limit,
+	})
+}
+
+// csvSafe stops spreadsheet apps from running user-supplied text as a formula
+// by prefixing cells that start with a formula character with a quote.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ExportRegistrations downloads an event's registrants as CSV.
+func ExportRegistrations(c *fiber.Ctx) error {
+	queries := db.New(database.DB)
+	event, handled, err := getManagedEvent(c, queries)
+	if handled {
+		return err
+	}
+
+	registrations, err := queries.ListAllEventRegistrations(c.Context(), event.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch registrations"})
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"Full name", "Email", "Role", "Registered at", "Checked in at"}); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to export registrations"})
+	}
+	for _, r := range registrations {
+		checkedInAt := ""
+		if r.CheckedInAt.Valid {
+			checkedInAt = r.CheckedInAt.Time.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			csvSafe(r.FullName),
+			csvSafe(r.Email),
+			string(r.Role),
+			r.CreatedAt.Time.UTC().Format(time.RFC3339),
+			checkedInAt,
+		}
+		if err := w.Write(record); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to export registrations"})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to export registrations"})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="registrations-%s.csv"`, event.ID))
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+	"unibook-go/util"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// sessionSubject is whoever a session is being issued for: a regular user or a super admin.
+type sessionSubject struct {
+	ID           uuid.UUID
+	Role         string
+	CollegeID    *uuid.UUID
+	IsSuperAdmin bool
+}
+
+// issueSession stores a new session row and returns the access/refresh token pair.
+// The refresh token has the form "<sessionId>.<secret>"; only a hash of the secret is stored.
+func issueSession(c *fiber.Ctx, cfg *config.Config, queries *db.Queries, subject sessionSubject) (fiber.Map, error) {
+	secret, err := util.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := queries.CreateSession(c.Context(), db.CreateSessionParams{
+		UserID:           subject.ID,
+		IsSuperAdmin:     subject.IsSuperAdmin,
+		RefreshTokenHash: util.HashToken(secret),
+		UserAgent:        pgtype.Text{String: c.Get(fiber.HeaderUserAgent), Valid: c.Get(fiber.HeaderUserAgent) != ""},
+		IpAddress:        pgtype.Text{String: c.IP(), Valid: true},
+		ExpiresAt:        pgtype.Timestamp{Time: time.Now().Add(cfg.RefreshTokenTTL), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := util.GenerateAccessToken(cfg, subject.ID, subject.Role, subject.CollegeID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return fiber.Map{
+		"token":        accessToken,
+		"refreshToken": session.ID.String() + "." + secret,
+		"expiresIn":    int(cfg.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// parseRefreshToken splits a refresh token into its session ID and secret.
+func parseRefreshToken(token string) (uuid.UUID, string, bool) {
+	sessionPart, secret, found := strings.Cut(token, ".")
+	if !found || secret == "" {
+		return uuid.Nil, "", false
+	}
+	sessionID, err := uuid.Parse(sessionPart)
+	if err != nil {
+		return uuid.Nil, "", false
+	}
+	return sessionID, secret, true
+}
+
+func RefreshToken(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var payload RefreshTokenPayload
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+		}
+
+		sessionID, secret, ok := parseRefreshToken(payload.RefreshToken)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid refresh token"})
+		}
+
+		queries := db.New(database.DB)
+
+		session, err := queries.GetSessionByID(c.Context(), sessionID)
+		if err != nil || session.RevokedAt.Valid || time.Now().After(session.ExpiresAt.Time) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid refresh token"})
+		}
+
+		oldHash := util.HashToken(secret)
+		if oldHash != session.RefreshTokenHash {
+			// An already rotated refresh token was replayed, so the token family
+			// is considered stolen and the whole session is shut down.
+			_ = queries.RevokeSession(c.Context(), session.ID)
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid refresh token"})
+		}
+
+		subject := sessionSubject{ID: session.UserID, IsSuperAdmin: session.IsSuperAdmin}
+		if session.IsSuperAdmin {
+			if _, err := queries.GetSuperAdminByID(c.Context(), session.UserID); err != nil {
+				_ = queries.RevokeSession(c.Context(), session.ID)
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid refresh token"})
+			}
+			subject.Role = "super_admin"
+		} else {
+			user, err := queries.GetUserByID(c.Context(), session.UserID)
+			if err != nil || user.ApprovalStatus != db.ApprovalStatusApproved {
+				_ = queries.RevokeSession(c.Context(), session.ID)
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid refresh token"})
+			}
+			subject.Role = string(user.Role)
+			subject.CollegeID = &user.CollegeId
+		}
+
+		newSecret, err := util.GenerateSecureToken(32)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate token"})
+		}
+
+		rows, err := queries.RotateSessionToken(c.Context(), db.RotateSessionTokenParams{
+			NewHash:   util.HashToken(newSecret),
+			ExpiresAt: pgtype.Timestamp{Time: time.Now().Add(cfg.RefreshTokenTTL), Valid: true},
+			ID:        session.ID,
+			OldHash:   oldHash,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to refresh session"})
+		}
+		if rows == 0 {
+			// Lost a race with a concurrent refresh using the same token.
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid refresh token"})
+		}
+
+		accessToken, err := util.GenerateAccessToken(cfg, subject.ID, subject.Role, subject.CollegeID, session.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate token"})
+		}
+
+		return c.JSON(fiber.Map{
+			"token":        accessToken,
+			"refreshToken": session.ID.String() + "." + newSecret,
+			"expiresIn":    int(cfg.AccessTokenTTL.Seconds()),
+		})
+	}
+}
+
+func Logout(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	queries := db.New(database.DB)
+	if err := queries.RevokeSession(c.Context(), authUser.SessionID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to log out"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Logged out successfully."})
+}
+
+func LogoutAll(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	queries := db.New(database.DB)
+	if err := queries.RevokeUserSessions(c.Context(), authUser.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to log out"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Logged out from all devices."})
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// defaultAssignmentRole matches the column default of event_staff_assignments.
+const defaultAssignmentRole = "staff in charge"
+
+type RequestStaffPayload struct {
+	UserID         uuid.UUID `json:"userId"`
+	AssignmentRole string    `json:"assignmentRole"`
+}
+
+func staffAssignmentResponse(assignment db.ListEventStaffRow) fiber.Map {
+	return fiber.Map{
+		"id":             assignment.ID,
+		"userId":         assignment.UserID,
+		"fullName":       assignment.FullName,
+		"email":          assignment.Email,
+		"assignmentRole": textPtr(assignment.AssignmentRole),
+		"status":         assignment.Status.ApprovalStatus,
+		"createdAt":      assignment.CreatedAt,
+	}
+}
+
+// RequestStaff lets the organizing forum ask a teacher of the college to be
+// staff in charge of an event.
+func RequestStaff(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	queries := db.New(database.DB)
+
+	event, err := getCollegeEvent(c, queries)
+	if err != nil {
+		return eventLookupErrorResponse(c, err)
+	}
+	if !middleware.IsVerifiedForumHead(c.Context(), queries, authUser, event.ForumID) {
+		return middleware.Forbidden(c, "Only verified heads of the organizing forum can request staff.")
+	}
+	if event.Status == db.EventStatusCancelled {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Cancelled events cannot take new staff.",
+			"code":  "EVENT_CANCELLED",
+		})
+	}
+
+	var payload RequestStaffPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	teacher, err := queries.GetUserByID(c.Context(), payload.UserID)
+	if err != nil || teacher.Role != db.UserRoleTeacher || teacher.CollegeId != event.CollegeID ||
+		teacher.ApprovalStatus != db.ApprovalStatusApproved {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Staff in charge must be an approved teacher of this college.",
+			"code":  "INVALID_STAFF",
+		})
+	}
+
+	assignmentRole := strings.TrimSpace(payload.AssignmentRole)
+	if assignmentRole == "" {
+		assignmentRole = defaultAssignmentRole
+	}
+
+	assignment, err := queries.CreateStaffAssignment(c.Context(), db.CreateStaffAssignmentParams{
+		EventID:        event.ID,
+		UserID:         teacher.ID,
+		AssignmentRole: pgtype.Text{String: assignmentRole, Valid: true},
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "This teacher has already been asked.",
+				"code":  "ALREADY_REQUESTED",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to request staff"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(staffAssignmentResponse(db.ListEventStaffRow{
+		ID:             assignment.ID,
+		AssignmentRole: assignment.AssignmentRole,
+		Status:         assignment.Status,
+		UserID:         teacher.ID,
+		FullName:       teacher.FullName,
+		Email:          teacher.Email,
+		CreatedAt:      assignment.CreatedAt,
+	}))
+}
+
+// RemoveStaff withdraws a staff request or removes a staff member from an event.
+// The last approved staff in charge of a confirmed event cannot be removed
+// while the college requires one.
+func RemoveStaff(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	queries := db.New(database.DB)
+
+	event, err := getCollegeEvent(c, queries)
+	if err != nil {
+		return eventLookupErrorResponse(c, err)
+	}
+	if !middleware.IsVerifiedForumHead(c.Context(), queries, authUser, event.ForumID) {
+		return middleware.Forbidden(c, "Only verified heads of the organizing forum can remove staff.")
+	}
+
+	assignmentID, err := uuid.Parse(c.Params("assignmentId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid assignment ID"})
+	}
+
+	tx, err := database.DB.Begin(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to remove staff"})
+	}
+	defer tx.Rollback(c.Context())
+	qtx := queries.WithTx(tx)
+
+	status, err := qtx.LockEventStatus(c.Context(), event.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to remove staff"})
+	}
+
+	rows, err := qtx.DeleteStaffAssignment(c.Context(), db.DeleteStaffAssignmentParams{
+		ID:      assignmentID,
+		EventID: event.ID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to remove staff"})
+	}
+	if rows == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Staff assignment not found"})
+	}
+
+	// A confirmed event must keep the staff in charge it was confirmed with.
+	if status == db.EventStatusConfirmed {
+		ready, err := hasRequiredStaff(c.Context(), qtx, event)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to remove staff"})
+		}
+		if !ready {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "A confirmed event must keep at least one staff in charge.",
+				"code":  "STAFF_REQUIRED",
+			})
+		}
+	}
+
+	if err := tx.Commit(c.Context()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to remove staff"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Staff removed successfully."})
+}
+
+// ListStaffRequests lists the staff-in-charge requests addressed to the
+// teacher. Filter with ?status=pending|approved|rejected.
+func ListStaffRequests(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	var status db.NullApprovalStatus
+	if s := c.Query("status"); s != "" {
+		switch as := db.ApprovalStatus(s); as {
+		case db.ApprovalStatusPending, db.ApprovalStatusApproved, db.ApprovalStatusRejected:
+			status = db.NullApprovalStatus{ApprovalStatus: as, Valid: true}
+		default:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid status"})
+		}
+	}
+
+	requests, err := db.New(database.DB).ListStaffRequestsForUser(c.Context(), db.ListStaffRequestsForUserParams{
+		UserID: authUser.ID,
+		Status: status,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch staff requests"})
+	}
+
+	items := make([]fiber.Map, 0, len(requests))
+	for _, request := range requests {
+		items = append(items, fiber.Map{
+			"id":             request.ID,
+			"assignmentRole": textPtr(request.AssignmentRole),
+			"status":         request.Status.ApprovalStatus,
+			"event": fiber.Map{
+				"id":        request.EventID,
+				"name":      request.EventName,
+				"startTime": request.StartTime,
+				"endTime":   request.EndTime,
+				"status":    request.EventStatus,
+				"forumName": request.ForumName,
+			},
+			"createdAt": request.CreatedAt,
+		})
+	}
+
+	return c.JSON(fiber.Map{"requests": items})
+}
+
+// respondToStaffRequest returns a handler with which a teacher accepts or
+// declines a request addressed to them.
+func respondToStaffRequest(status db.ApprovalStatus) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+
+		assignmentID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request ID"})
+		}
+
+		queries := db.New(database.DB)
+
+		assignment, err := queries.RespondToStaffAssignment(c.Context(), db.RespondToStaffAssignmentParams{
+			Status: db.NullApprovalStatus{ApprovalStatus: status, Valid: true},
+			ID:     assignmentID,
+			UserID: authUser.ID,
+		})
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to respond to request"})
+			}
+			existing, err := queries.GetStaffAssignment(c.Context(), assignmentID)
+			if err != nil || existing.UserID != authUser.ID {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Staff request not found"})
+			}
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":  "This request has already been answered.",
+				"code":   "ALREADY_ANSWERED",
+				"status": existing.Status.ApprovalStatus,
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"id":      assignment.ID,
+			"eventId": assignment.EventID,
+			"status":  assignment.Status.ApprovalStatus,
+		})
+	}
+}
+
+var (
+	AcceptStaffRequest  = respondToStaffRequest(db.ApprovalStatusApproved)
+	DeclineStaffRequest = respondToStaffRequest(db.ApprovalStatusRejected)
+)
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+	"unibook-go/util"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	// ticketGracePeriod keeps tickets valid for a while after the event ends,
+	// for late check-ins at long events.
+	ticketGracePeriod = 12 * time.Hour
+
+	defaultTicketSize = 512
+	minTicketSize     = 128
+	maxTicketSize     = 1024
+)
+
+type CheckInPayload struct {
+	Ticket string `json:"ticket"`
+}
+
+func attendeeResponse(attendee db.GetRegistrationAttendeeRow) fiber.Map {
+	return fiber.Map{
+		"registrationId": attendee.ID,
+		"userId":         attendee.UserID,
+		"fullName":       attendee.FullName,
+		"email":          attendee.Email,
+		"checkedInAt":    attendee.CheckedInAt,
+	}
+}
+
+// GetTicketQR renders the user's ticket for an event as a QR code PNG. Size it
+// with ?size= (in pixels).
+func GetTicketQR(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+		queries := db.New(database.DB)
+
+		event, err := getCollegeEvent(c, queries)
+		if err != nil {
+			return eventLookupErrorResponse(c, err)
+		}
+		if event.Status == db.EventStatusCancelled {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "This event has been cancelled.",
+				"code":  "EVENT_CANCELLED",
+			})
+		}
+
+		registration, err := queries.GetEventRegistration(c.Context(), db.GetEventRegistrationParams{
+			EventID: event.ID,
+			UserID:  authUser.ID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "You are not registered for this event"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create ticket"})
+		}
+
+		size := c.QueryInt("size", defaultTicketSize)
+		size = min(max(size, minTicketSize), maxTicketSize)
+
+		ticket, err := util.GenerateEventTicket(cfg, registration.ID, event.ID, event.EndTime.Time.Add(ticketGracePeriod))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create ticket"})
+		}
+		png, err := qrcode.Encode(ticket, qrcode.Medium, size)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create ticket"})
+		}
+
+		c.Set(fiber.HeaderContentType, "image/png")
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+		return c.Send(png)
+	}
+}
+
+// CheckInAttendee verifies a scanned ticket and marks its holder as attended.
+// Each ticket is accepted once; scanning it again is reported as a replay.
+func CheckInAttendee(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+		queries := db.New(database.DB)
+
+		event, err := getCollegeEvent(c, queries)
+		if err != nil {
+			return eventLookupErrorResponse(c, err)
+		}
+
+		roles, err := eventRolesOf(c.Context(), queries, authUser, event)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check in"})
+		}
+		if roles == 0 {
+			return middleware.Forbidden(c, "Only the organizers, co-hosts, staff and college admins can check attendees in.")
+		}
+		if event.Status != db.EventStatusConfirmed {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":  "Only confirmed events take check-ins.",
+				"code":   "EVENT_NOT_CONFIRMED",
+				"status": event.Status,
+			})
+		}
+
+		var payload CheckInPayload
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+		}
+
+		registrationID, ticketEventID, err := util.ParseEventTicket(cfg, payload.Ticket)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "This ticket is not valid.",
+				"code":  "INVALID_TICKET",
+			})
+		}
+		if ticketEventID != event.ID {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "This ticket is for a different event.",
+				"code":  "WRONG_EVENT",
+			})
+		}
+
+		_, err = queries.CheckInRegistration(c.Context(), db.CheckInRegistrationParams{
+			CheckedInBy: pgtype.UUID{Bytes: authUser.ID, Valid: true},
+			ID:          registrationID,
+			EventID:     event.ID,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check in"})
+		}
+		checkedIn := err == nil
+
+		attendee, err := queries.GetRegistrationAttendee(c.Context(), registrationID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The registration was cancelled after the ticket was issued.
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "This ticket's registration no longer exists.",
+				"code":  "REGISTRATION_NOT_FOUND",
+			})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check in"})
+		}
+		if !checkedIn {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":    "This ticket has already been used.",
+				"code":     "ALREADY_CHECKED_IN",
+				"attendee": attendeeResponse(attendee),
+			})
+		}
+
+		count, err := queries.CountCheckedInRegistrations(c.Context(), event.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check in"})
+		}
+
+		return c.JSON(fiber.Map{
+			"attendee":       attendeeResponse(attendee),
+			"checkedInCount": count,
+		})
+	}
+}
+
+// GetCheckInStats shows how many registrants have been checked in so far.
+// Scanning apps poll it, so it is never cached.
+func GetCheckInStats(c *fiber.Ctx) error {
+	queries := db.New(database.DB)
+	event, handled, err := getManagedEvent(c, queries)
+	if handled {
+		return err
+	}
+
+	checkedIn, err := queries.CountCheckedInRegistrations(c.Context(), event.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch check-ins"})
+	}
+	registered, err := queries.CountEventRegistrations(c.Context(), event.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch check-ins"})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(fiber.Map{
+		"checkedInCount":  checkedIn,
+		"registeredCount": registered,
+	})
+}
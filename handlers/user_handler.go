@@ -2,21 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
-	"log"
-	"math/rand"
-	"time"
+	"errors"
 
 	"unibook-go/config"
 	"unibook-go/database"
 	db "unibook-go/database/db"
 	"unibook-go/middleware"
+	"unibook-go/otp"
 	"unibook-go/util"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgtype"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -49,9 +45,8 @@ type VerifyForgotPasswordOtpPayload struct {
 }
 
 type ResetPasswordPayload struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Otp      string `json:"otp"`
+	ResetToken string `json:"resetToken"`
+	Password   string `json:"password"`
 }
 
 type College struct {
@@ -77,12 +72,52 @@ func RegisterUser(cfg *config.Config) fiber.Handler {
 
 		queries := db.New(database.DB)
 
-		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(payload.Password), 10)
+		college, err := queries.GetCollegeByID(c.Context(), payload.CollegeID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "The selected college does not exist.",
+				"code":  "INVALID_COLLEGE",
+			})
+		}
+
+		if allowedDomains := collegeEmailDomains(college); len(allowedDomains) > 0 {
+			domain, ok := util.EmailDomain(payload.Email)
+			if !ok || !util.DomainAllowed(domain, allowedDomains) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":          "Please register with your college email address.",
+					"code":           "EMAIL_DOMAIN_MISMATCH",
+					"allowedDomains": allowedDomains,
+				})
+			}
+		}
+
+		if db.UserRole(payload.Role) == db.UserRoleForumHead && payload.ForumID != uuid.Nil {
+			forum, err := queries.GetForumByID(c.Context(), payload.ForumID)
+			if err != nil || forum.CollegeID != college.ID || forum.ArchivedAt.Valid {
+				return invalidForumResponse(c)
+			}
+		}
+
+		if _, err := queries.GetUserByEmail(c.Context(), payload.Email); err == nil {
+			return emailTakenResponse(c)
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(payload.Password), 10)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create user account."})
+		}
 		approvalStatus := db.ApprovalStatusPending
 		if db.UserRole(payload.Role) == db.UserRoleStudent {
 			approvalStatus = db.ApprovalStatusApproved
 		}
 
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create user account."})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
+
 		userParams := db.CreateUserParams{
 			FullName:       payload.FullName,
 			Email:          payload.Email,
@@ -91,8 +126,11 @@ func RegisterUser(cfg *config.Config) fiber.Handler {
 			CollegeID:      payload.CollegeID,
 			ApprovalStatus: approvalStatus,
 		}
-		newUser, err := queries.CreateUser(c.Context(), userParams)
+		newUser, err := qtx.CreateUser(c.Context(), userParams)
 		if err != nil {
+			if database.IsUniqueViolation(err) {
+				return emailTakenResponse(c)
+			}
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create user account."})
 		}
 
@@ -101,21 +139,28 @@ func RegisterUser(cfg *config.Config) fiber.Handler {
 				UserID:  newUser.ID,
 				ForumID: payload.ForumID,
 			}
-			_, _ = queries.CreateForumHead(c.Context(), forumHeadParams)
+			if _, err := qtx.CreateForumHead(c.Context(), forumHeadParams); err != nil {
+				if database.IsForeignKeyViolation(err) {
+					return invalidForumResponse(c)
+				}
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create user account."})
+			}
 		}
 
-		otp := fmt.Sprintf("%04d", rand.Intn(10000))
-		log.Printf("Generated OTP for %s: %s", newUser.Email, otp)
-		hashedOtp, _ := bcrypt.GenerateFromPassword([]byte(otp), 10)
+		code, err := otp.Issue(c.Context(), qtx, cfg, newUser.ID, db.OtpPurposeEmailVerification)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create user account."})
+		}
 
-		verificationParams := db.SetUserEmailVerificationDetailsParams{
-			ID:                       newUser.ID,
-			EmailVerificationToken:   pgtype.Text{String: string(hashedOtp), Valid: true},
-			EmailVerificationExpires: pgtype.Timestamp{Time: time.Now().Add(10 * time.Minute), Valid: true},
+		if err := tx.Commit(c.Context()); err != nil {
+			if database.IsUniqueViolation(err) {
+				return emailTakenResponse(c)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Could not create user account."})
 		}
-		_ = queries.SetUserEmailVerificationDetails(c.Context(), verificationParams)
 
-		go util.SendOtpEmail(cfg, newUser.Email, otp)
+		// Only send the email once the account is actually stored.
+		go util.SendOtpEmail(cfg, newUser.Email, code)
 
 		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 			"message": "Registration successful. Please check your email for a verification code.",
@@ -123,6 +168,58 @@ func RegisterUser(cfg *config.Config) fiber.Handler {
 	}
 }
 
+func emailTakenResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error": "An account with this email already exists.",
+		"code":  "EMAIL_TAKEN",
+	})
+}
+
+func invalidForumResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error": "The selected forum does not exist in this college.",
+		"code":  "INVALID_FORUM",
+	})
+}
+
+// collegeEmailDomains lists the email domains a college accepts at registration.
+// An empty result means any address is accepted, either because the college has
+// no domain configured or because a super admin switched enforcement off.
+func collegeEmailDomains(college db.College) []string {
+	if college.EmailDomainOverride {
+		return nil
+	}
+
+	domains := make([]string, 0, len(college.AllowedEmailDomains)+1)
+	if college.DomainName.Valid && college.DomainName.String != "" {
+		domains = append(domains, college.DomainName.String)
+	}
+	return append(domains, college.AllowedEmailDomains...)
+}
+
+// otpErrorResponse maps otp package errors to the API's error responses.
+func otpErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, otp.ErrLocked):
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error": "Too many incorrect attempts. Please try again later.",
+			"code":  "OTP_LOCKED",
+		})
+	case errors.Is(err, otp.ErrExpired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "OTP has expired. Please request a new one.",
+			"code":  "OTP_EXPIRED",
+		})
+	case errors.Is(err, otp.ErrInvalid):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid OTP.",
+			"code":  "OTP_INVALID",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Internal Server Error"})
+	}
+}
+
 func VerifyOtpAndLogin(cfg *config.Config) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		payload := new(VerifyOtpPayload)
@@ -139,13 +236,9 @@ func VerifyOtpAndLogin(cfg *config.Config) fiber.Handler {
 		if user.IsEmailVerified {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Email is already verified."})
 		}
-		if !user.EmailVerificationToken.Valid || time.Now().After(user.EmailVerificationExpires.Time) {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid OTP or request has expired."})
-		}
 
-		err = bcrypt.CompareHashAndPassword([]byte(user.EmailVerificationToken.String), []byte(payload.OTP))
-		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid OTP."})
+		if err := otp.Verify(c.Context(), queries, user.ID, db.OtpPurposeEmailVerification, payload.OTP); err != nil {
+			return otpErrorResponse(c, err)
 		}
 
 		updatedUser, err := queries.VerifyUserEmail(c.Context(), user.ID)
@@ -160,18 +253,17 @@ func VerifyOtpAndLogin(cfg *config.Config) fiber.Handler {
 			})
 		}
 
-		claims := jwt.MapClaims{
-			"id":        updatedUser.ID.String(),
-			"role":      updatedUser.Role,
-			"collegeId": updatedUser.CollegeID.String(),
-		}
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-		t, err := token.SignedString([]byte(cfg.JWTSecret))
+		tokens, err := issueSession(c, cfg, queries, sessionSubject{
+			ID:        updatedUser.ID,
+			Role:      string(updatedUser.Role),
+			CollegeID: &updatedUser.CollegeID,
+		})
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).SendString("Failed to generate token")
 		}
 
-		return c.JSON(fiber.Map{"message": "Email verified successfully.", "token": t})
+		tokens["message"] = "Email verified successfully."
+		return c.JSON(tokens)
 	}
 }
 
@@ -192,13 +284,15 @@ func Login(cfg *config.Config) fiber.Handler {
 		if err == nil {
 			err := bcrypt.CompareHashAndPassword([]byte(superAdmin.PasswordHash), []byte(body.Password))
 			if err == nil {
-				claims := jwt.MapClaims{
-					"id":   superAdmin.ID,
-					"role": "super_admin",
+				tokens, err := issueSession(c, cfg, queries, sessionSubject{
+					ID:           superAdmin.ID,
+					Role:         "super_admin",
+					IsSuperAdmin: true,
+				})
+				if err != nil {
+					return c.Status(fiber.StatusInternalServerError).SendString("Failed to generate token")
 				}
-				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-				t, _ := token.SignedString([]byte(cfg.JWTSecret))
-				return c.JSON(fiber.Map{"token": t})
+				return c.JSON(tokens)
 			}
 		}
 
@@ -234,18 +328,16 @@ func Login(cfg *config.Config) fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid credentials."})
 		}
 
-		claims := jwt.MapClaims{
-			"id":        user.ID.String(),
-			"role":      user.Role,
-			"collegeId": user.CollegeID.String(),
-		}
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-		t, err := token.SignedString([]byte(cfg.JWTSecret))
+		tokens, err := issueSession(c, cfg, queries, sessionSubject{
+			ID:        user.ID,
+			Role:      string(user.Role),
+			CollegeID: &user.CollegeID,
+		})
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).SendString("Failed to generate token")
 		}
 
-		return c.JSON(fiber.Map{"token": t})
+		return c.JSON(tokens)
 	}
 }
 
@@ -261,25 +353,16 @@ func ResendOtp(cfg *config.Config) fiber.Handler {
 
 		user, err := queries.GetUserByEmail(c.Context(), payload.Email)
 
-		if err != nil {
+		if err != nil || user.IsEmailVerified {
 			return c.JSON(fiber.Map{"message": "otp send"})
 		}
 
-		otp := fmt.Sprintf("%04d", rand.Intn(10000))
-		hashedOtp, _ := bcrypt.GenerateFromPassword([]byte(otp), 10)
-		verificationParam := db.SetUserEmailVerificationDetailsParams{
-			ID:                       user.ID,
-			EmailVerificationToken:   pgtype.Text{String: string(hashedOtp), Valid: true},
-			EmailVerificationExpires: pgtype.Timestamp{Time: time.Now().Add(10 * time.Minute), Valid: true},
-		}
-
-		err = queries.SetUserEmailVerificationDetails(c.Context(), verificationParam)
-
+		code, err := otp.Issue(c.Context(), queries, cfg, user.ID, db.OtpPurposeEmailVerification)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Internal Server Error"})
+			return otpErrorResponse(c, err)
 		}
 
-		go util.SendOtpEmail(cfg, user.Email, otp)
+		go util.SendOtpEmail(cfg, user.Email, code)
 
 		return c.JSON(fiber.Map{
 			"message": "A new verification code has been sent to your email.",
@@ -308,23 +391,12 @@ func ForgotPassword(cfg *config.Config) fiber.Handler {
 			return c.JSON(fiber.Map{"message": "otp send"})
 		}
 
-		otp := fmt.Sprintf("%04d", rand.Intn(10000))
-
-		hashedOtp, _ := bcrypt.GenerateFromPassword([]byte(otp), 10)
-
-		forgotPasswordParams := db.SetUserPasswordResetDetailsParams{
-			ID:                   user.ID,
-			PasswordResetToken:   pgtype.Text{String: string(hashedOtp), Valid: true},
-			PasswordResetExpires: pgtype.Timestamp{Time: time.Now().Add(10 * time.Minute), Valid: true},
-		}
-
-		err = queries.SetUserPasswordResetDetails(c.Context(), forgotPasswordParams)
-
+		code, err := otp.Issue(c.Context(), queries, cfg, user.ID, db.OtpPurposePasswordReset)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Internal Server Error"})
+			return otpErrorResponse(c, err)
 		}
 
-		go util.SendOtpEmail(cfg, user.Email, otp)
+		go util.SendOtpEmail(cfg, user.Email, code)
 
 		return c.JSON(fiber.Map{
 			"message": "A password reset code has been sent to your email.",
@@ -332,75 +404,102 @@ func ForgotPassword(cfg *config.Config) fiber.Handler {
 	}
 }
 
-func VerifyResetOtp(c *fiber.Ctx) error {
-	var payload VerifyForgotPasswordOtpPayload
+func VerifyResetOtp(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var payload VerifyForgotPasswordOtpPayload
 
-	if err := c.BodyParser(&payload); err != nil {
-		return c.Status(403).JSON(fiber.Map{"error": "Invalid Json"})
-	}
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(403).JSON(fiber.Map{"error": "Invalid Json"})
+		}
 
-	if payload.Email == "" || payload.Otp == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "payload is Required"})
-	}
+		if payload.Email == "" || payload.Otp == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "payload is Required"})
+		}
 
-	queries := db.New(database.DB)
+		queries := db.New(database.DB)
 
-	user, err := queries.GetUserByEmail(c.Context(), payload.Email)
+		user, err := queries.GetUserByEmail(c.Context(), payload.Email)
 
-	if err != nil || !user.PasswordResetToken.Valid || user.PasswordResetToken.String == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired reset token"})
-	}
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired reset token"})
+		}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordResetToken.String), []byte(payload.Otp))
+		if err := otp.Verify(c.Context(), queries, user.ID, db.OtpPurposePasswordReset, payload.Otp); err != nil {
+			return otpErrorResponse(c, err)
+		}
 
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid OTP."})
-	}
+		ticket, err := util.GenerateResetTicket(cfg, user.ID, user.PasswordHash)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate reset token"})
+		}
 
-	return c.JSON(fiber.Map{
-		"message": "OTP verified successfully.",
-	})
+		return c.JSON(fiber.Map{
+			"message":    "OTP verified successfully.",
+			"resetToken": ticket,
+			"expiresIn":  int(cfg.ResetTicketTTL.Seconds()),
+		})
+	}
 }
 
-func ResetPassword(c *fiber.Ctx) error {
-	var payload ResetPasswordPayload
+func ResetPassword(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var payload ResetPasswordPayload
 
-	if err := c.BodyParser(&payload); err != nil {
-		return c.Status(403).JSON(fiber.Map{"error": "Invalid Json"})
-	}
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(403).JSON(fiber.Map{"error": "Invalid Json"})
+		}
 
-	if payload.Email == "" || payload.Otp == "" || payload.Password == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "payload is Required"})
-	}
+		if payload.ResetToken == "" || payload.Password == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "payload is Required"})
+		}
 
-	queries := db.New(database.DB)
+		userID, fingerprint, err := util.ParseResetTicket(cfg, payload.ResetToken)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired reset token"})
+		}
 
-	user, err := queries.GetUserByEmail(c.Context(), payload.Email)
+		queries := db.New(database.DB)
 
-	if err != nil || !user.PasswordResetToken.Valid || user.PasswordResetToken.String == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired reset token"})
-	}
+		oldHash, err := queries.GetUserPasswordHash(c.Context(), userID)
+		if err != nil || util.HashToken(oldHash) != fingerprint {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired reset token"})
+		}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordResetToken.String), []byte(payload.Otp))
+		newPassword, _ := bcrypt.GenerateFromPassword([]byte(payload.Password), 10)
 
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid OTP."})
-	}
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update password"})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
 
-	newPassword, _ := bcrypt.GenerateFromPassword([]byte(payload.Password), 10)
-	updatePasswordParam := db.UpdateUserPasswordParams{
-		ID:           user.ID,
-		PasswordHash: string(newPassword),
-	}
+		rows, err := qtx.ResetUserPassword(c.Context(), db.ResetUserPasswordParams{
+			NewHash: string(newPassword),
+			ID:      userID,
+			OldHash: oldHash,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update password"})
+		}
+		if rows == 0 {
+			// The ticket was already used by a concurrent request.
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid or expired reset token"})
+		}
 
-	err = queries.UpdateUserPassword(c.Context(), updatePasswordParam)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update password"})
-	}
+		// Sign the user out everywhere, in case the reset was prompted by a compromised account.
+		if err := qtx.RevokeUserSessions(c.Context(), userID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update password"})
+		}
 
-	return c.JSON(fiber.Map{
-		"message": "Password reset successfully.",
-	})
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update password"})
+		}
+
+		return c.JSON(fiber.Map{
+			"message": "Password reset successfully.",
+		})
+	}
 }
 
 func GetMe(c *fiber.Ctx) error {
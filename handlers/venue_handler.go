@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+	"unibook-go/registration"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxAvailabilityRange caps the window a single availability query may cover.
+const maxAvailabilityRange = 92 * 24 * time.Hour
+
+type CreateVenuePayload struct {
+	Name            string `json:"name"`
+	Capacity        int32  `json:"capacity"`
+	LocationDetails string `json:"locationDetails"`
+}
+
+type UpdateVenuePayload struct {
+	Name            *string `json:"name"`
+	Capacity        *int32  `json:"capacity"`
+	LocationDetails *string `json:"locationDetails"`
+	IsActive        *bool   `json:"isActive"`
+}
+
+func venueResponse(venue db.Venue) fiber.Map {
+	return fiber.Map{
+		"id":              venue.ID,
+		"name":            venue.Name,
+		"capacity":        venue.Capacity,
+		"locationDetails": textPtr(venue.LocationDetails),
+		"isActive":        !venue.IsActive.Valid || venue.IsActive.Bool,
+		"collegeId":       venue.CollegeID,
+		"createdAt":       venue.CreatedAt,
+	}
+}
+
+// getCollegeVenue loads the venue in the :id route param. Venues of other
+// colleges are reported as pgx.ErrNoRows.
+func getCollegeVenue(c *fiber.Ctx, queries *db.Queries) (db.Venue, error) {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	venueID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return db.Venue{}, pgx.ErrNoRows
+	}
+
+	venue, err := queries.GetVenueByID(c.Context(), venueID)
+	if err != nil {
+		return db.Venue{}, err
+	}
+	if venue.CollegeID != *authUser.CollegeID {
+		return db.Venue{}, pgx.ErrNoRows
+	}
+	return venue, nil
+}
+
+func venueLookupErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Venue not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch venue"})
+}
+
+// ListVenues lists the venues of the user's college. College admins can include
+// inactive venues with ?includeInactive=true.
+func ListVenues(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	page, limit := parsePagination(c)
+	search := strings.TrimSpace(c.Query("search"))
+	includeInactive := authUser.Role == string(db.UserRoleCollegeAdmin) && c.QueryBool("includeInactive")
+
+	queries := db.New(database.DB)
+
+	venues, err := queries.ListCollegeVenues(c.Context(), db.ListCollegeVenuesParams{
+		CollegeID:       *authUser.CollegeID,
+		IncludeInactive: includeInactive,
+		Search:          search,
+		PageLimit:       int32(limit),
+		PageOffset:      int32((page - 1) * limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch venues"})
+	}
+
+	total, err := queries.CountCollegeVenues(c.Context(), db.CountCollegeVenuesParams{
+		CollegeID:       *authUser.CollegeID,
+		IncludeInactive: includeInactive,
+		Search:          search,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch venues"})
+	}
+
+	items := make([]fiber.Map, 0, len(venues))
+	for _, venue := range venues {
+		items = append(items, venueResponse(venue))
+	}
+
+	return c.JSON(fiber.Map{
+		"venues": items,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+func GetVenue(c *fiber.Ctx) error {
+	venue, err := getCollegeVenue(c, db.New(database.DB))
+	if err != nil {
+		return venueLookupErrorResponse(c, err)
+	}
+	return c.JSON(venueResponse(venue))
+}
+
+func CreateVenue(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+
+	var payload CreateVenuePayload
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	name := strings.TrimSpace(payload.Name)
+	if name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Venue name is required"})
+	}
+	if payload.Capacity <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Capacity must be greater than zero"})
+	}
+
+	venue, err := db.New(database.DB).CreateVenue(c.Context(), db.CreateVenueParams{
+		Name:            name,
+		Capacity:        payload.Capacity,
+		LocationDetails: optionalText(payload.LocationDetails),
+		CollegeID:       *authUser.CollegeID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create venue"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(venueResponse(venue))
+}
+
+// UpdateVenue edits a venue. When its capacity grows, the new spots of upcoming
+// events held there are offered to their waitlists.
+func UpdateVenue(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+
+		venueID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid venue ID"})
+		}
+
+		var payload UpdateVenuePayload
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+		}
+
+		params := db.UpdateVenueParams{ID: venueID, CollegeID: *authUser.CollegeID}
+		if payload.Name != nil {
+			name := strings.TrimSpace(*payload.Name)
+			if name == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Venue name cannot be empty"})
+			}
+			params.Name = pgtype.Text{String: name, Valid: true}
+		}
+		if payload.Capacity != nil {
+			if *payload.Capacity <= 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Capacity must be greater than zero"})
+			}
+			params.Capacity = pgtype.Int4{Int32: *payload.Capacity, Valid: true}
+		}
+		if payload.LocationDetails != nil {
+			params.LocationDetails = pgtype.Text{String: strings.TrimSpace(*payload.LocationDetails), Valid: true}
+		}
+		if payload.IsActive != nil {
+			params.IsActive = pgtype.Bool{Bool: *payload.IsActive, Valid: true}
+		}
+
+		queries := db.New(database.DB)
+		var previousCapacity int32
+		if payload.Capacity != nil {
+			previous, err := getCollegeVenue(c, queries)
+			if err != nil {
+				return venueLookupErrorResponse(c, err)
+			}
+			previousCapacity = previous.Capacity
+		}
+
+		venue, err := queries.UpdateVenue(c.Context(), params)
+		if err != nil {
+			return venueLookupErrorResponse(c, err)
+		}
+
+		if payload.Capacity != nil && venue.Capacity > previousCapacity {
+			eventIDs, err := queries.ListUpcomingVenueEventIDs(c.Context(), db.ListUpcomingVenueEventIDsParams{
+				VenueID: pgtype.UUID{Bytes: venue.ID, Valid: true},
+				Now:     registration.Now(),
+			})
+			if err != nil {
+				log.Printf("Failed to list events at venue %s: %v", venue.ID, err)
+			}
+			// PromoteEvent waits for its emails, so hand the spots out after responding.
+			go func() {
+				for _, eventID := range eventIDs {
+					if err := registration.PromoteEvent(context.Background(), cfg, eventID); err != nil {
+						log.Printf("Failed to promote waitlist of event %s: %v", eventID, err)
+					}
+				}
+			}()
+		}
+
+		return c.JSON(venueResponse(venue))
+	}
+}
+
+func DeleteVenue(c *fiber.Ctx) error {
+	authUser := c.Locals("authUser").(middleware.AuthUser)
+	queries := db.New(database.DB)
+
+	// Check ownership first so the event count of other colleges' venues
+	// is never revealed.
+	venue, err := getCollegeVenue(c, queries)
+	if err != nil {
+		return venueLookupErrorResponse(c, err)
+	}
+
+	eventCount, err := queries.CountVenueEvents(c.Context(), pgtype.UUID{Bytes: venue.ID, Valid: true})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete venue"})
+	}
+
+	if eventCount > 0 && !c.QueryBool("force") {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":  "Events are using this venue. Deleting it will leave them without a venue. Deactivate it instead, or repeat the request with ?force=true to confirm.",
+			"code":   "VENUE_IN_USE",
+			"events": eventCount,
+		})
+	}
+
+	rows, err := queries.DeleteVenue(c.Context(), db.DeleteVenueParams{
+		ID:        venue.ID,
+		CollegeID: *authUser.CollegeID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete venue"})
+	}
+	if rows == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Venue not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Venue deleted successfully."})
+}
+
+// GetVenueAvailability splits [from, to) into busy and free blocks for a venue.
+// Busy blocks come from every non-cancelled event, drafts included, since they
+// all hold the venue; only confirmed events are named, since the rest are not
+// public yet.
+func GetVenueAvailability(c *fiber.Ctx) error {
+	queries := db.New(database.DB)
+
+	venue, err := getCollegeVenue(c, queries)
+	if err != nil {
+		return venueLookupErrorResponse(c, err)
+	}
+
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "from must be an RFC 3339 timestamp"})
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "to must be an RFC 3339 timestamp"})
+	}
+	from, to = from.UTC(), to.UTC()
+	if !to.After(from) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "to must be after from"})
+	}
+	if to.Sub(from) > maxAvailabilityRange {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "The requested range is too long"})
+	}
+
+	bookings, err := queries.ListVenueBookings(c.Context(), db.ListVenueBookingsParams{
+		VenueID:    pgtype.UUID{Bytes: venue.ID, Valid: true},
+		RangeStart: eventTimestamp(from),
+		RangeEnd:   eventTimestamp(to),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch availability"})
+	}
+
+	busy := make([]fiber.Map, 0, len(bookings))
+	free := make([]fiber.Map, 0, len(bookings)+1)
+	cursor := from
+	for _, booking := range bookings {
+		start := maxTime(booking.StartTime.Time, from)
+		end := minTime(booking.EndTime.Time, to)
+
+		block := fiber.Map{"start": start, "end": end, "status": booking.Status}
+		if booking.Status == db.EventStatusConfirmed {
+			block["eventId"] = booking.ID
+			block["eventName"] = booking.Name
+		}
+		busy = append(busy, block)
+
+		if start.After(cursor) {
+			free = append(free, fiber.Map{"start": cursor, "end": start})
+		}
+		cursor = maxTime(cursor, end)
+	}
+	if to.After(cursor) {
+		free = append(free, fiber.Map{"start": cursor, "end": to})
+	}
+
+	return c.JSON(fiber.Map{
+		"venueId": venue.ID,
+		"from":    from,
+		"to":      to,
+		"busy":    busy,
+		"free":    free,
+	})
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
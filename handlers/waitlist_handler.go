@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/middleware"
+	"unibook-go/registration"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RegistrationLimitPayload struct {
+	// RegistrationLimit is the new limit; null removes it.
+	RegistrationLimit *int32 `json:"registrationLimit"`
+}
+
+// waitlistEntryResponse shows a waitlist entry. Entries holding an offer have
+// no position; they have until offerExpiresAt to register.
+func waitlistEntryResponse(ctx context.Context, queries *db.Queries, entry db.EventWaitlistEntry) (fiber.Map, error) {
+	var position *int64
+	if !entry.OfferedAt.Valid {
+		p, err := queries.GetWaitlistPosition(ctx, entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		position = &p
+	}
+
+	return fiber.Map{
+		"id":             entry.ID,
+		"joinedAt":       entry.CreatedAt,
+		"position":       position,
+		"offeredAt":      entry.OfferedAt,
+		"offerExpiresAt": entry.OfferExpiresAt,
+	}, nil
+}
+
+// JoinWaitlist puts the user in line for a full event.
+func JoinWaitlist(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+		queries := db.New(database.DB)
+
+		event, err := getCollegeEvent(c, queries)
+		if err != nil {
+			return eventLookupErrorResponse(c, err)
+		}
+		visible, err := canViewEvent(c.Context(), queries, authUser, event)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+		}
+		if !visible {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Event not found"})
+		}
+
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
+
+		locked, err := qtx.LockEventForRegistration(c.Context(), event.ID)
+		if err != nil {
+			return eventLookupErrorResponse(c, err)
+		}
+		if !registrationOpen(locked.Status, locked.StartTime) {
+			return registrationClosedResponse(c)
+		}
+
+		_, err = qtx.GetEventRegistration(c.Context(), db.GetEventRegistrationParams{EventID: event.ID, UserID: authUser.ID})
+		if err == nil {
+			return alreadyRegisteredResponse(c)
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+		}
+
+		full := false
+		if capacity := registration.Capacity(locked.RegistrationLimit, locked.VenueCapacity); capacity != nil {
+			registered, err := qtx.CountEventRegistrations(c.Context(), event.ID)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+			}
+			offered, err := qtx.CountActiveWaitlistOffers(c.Context(), db.CountActiveWaitlistOffersParams{
+				EventID: event.ID,
+				Now:     registration.Now(),
+			})
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+			}
+			waiting, err := qtx.CountWaitingEntries(c.Context(), event.ID)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+			}
+			full = registered+offered >= int64(*capacity) || waiting > 0
+		}
+		if !full {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "This event still has free spots. Register instead.",
+				"code":  "SPOTS_AVAILABLE",
+			})
+		}
+
+		_, err = qtx.CreateWaitlistEntry(c.Context(), db.CreateWaitlistEntryParams{
+			EventID: event.ID,
+			UserID:  authUser.ID,
+		})
+		if err != nil {
+			if database.IsUniqueViolation(err) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "You are already on the waitlist for this event.",
+					"code":  "ALREADY_WAITLISTED",
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+		}
+
+		// Spots left behind by expired offers may be waiting to be passed on.
+		offers, err := registration.Promote(c.Context(), qtx, cfg, event.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+		}
+
+		entry, err := qtx.GetWaitlistEntry(c.Context(), db.GetWaitlistEntryParams{EventID: event.ID, UserID: authUser.ID})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+		}
+		response, err := waitlistEntryResponse(c.Context(), qtx, entry)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to join the waitlist"})
+		}
+		go registration.Notify(cfg, offers)
+
+		return c.Status(fiber.StatusCreated).JSON(response)
+	}
+}
+
+// LeaveWaitlist takes the user out of line. A spot they were offered passes to
+// the next person.
+func LeaveWaitlist(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+		queries := db.New(database.DB)
+
+		event, err := getCollegeEvent(c, queries)
+		if err != nil {
+			return eventLookupErrorResponse(c, err)
+		}
+
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to leave the waitlist"})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
+
+		_, err = qtx.DeleteWaitlistEntry(c.Context(), db.DeleteWaitlistEntryParams{EventID: event.ID, UserID: authUser.ID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "You are not on the waitlist for this event"})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to leave the waitlist"})
+		}
+
+		offers, err := registration.Promote(c.Context(), qtx, cfg, event.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to leave the waitlist"})
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to leave the waitlist"})
+		}
+		go registration.Notify(cfg, offers)
+
+		return c.JSON(fiber.Map{"message": "You have left the waitlist."})
+	}
+}
+
+// ListWaitlist shows an event's waitlist in the order spots are offered.
+func ListWaitlist(c *fiber.Ctx) error {
+	queries := db.New(database.DB)
+	event, handled, err := getManagedEvent(c, queries)
+	if handled {
+		return err
+	}
+
+	entries, err := queries.ListEventWaitlist(c.Context(), event.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch the waitlist"})
+	}
+
+	items := make([]fiber.Map, 0, len(entries))
+	var position int64
+	for _, entry := range entries {
+		var entryPosition *int64
+		if !entry.OfferedAt.Valid {
+			position++
+			p := position
+			entryPosition = &p
+		}
+		items = append(items, fiber.Map{
+			"id":             entry.ID,
+			"userId":         entry.UserID,
+			"fullName":       entry.FullName,
+			"email":          entry.Email,
+			"joinedAt":       entry.CreatedAt,
+			"position":       entryPosition,
+			"offeredAt":      entry.OfferedAt,
+			"offerExpiresAt": entry.OfferExpiresAt,
+		})
+	}
+
+	return c.JSON(fiber.Map{"waitlist": items})
+}
+
+// UpdateRegistrationLimit changes the registration limit of an event that may
+// already take registrations. Raising it offers the new spots to the waitlist;
+// lowering it below the current registrations keeps everyone registered but
+// admits nobody new. Only the organizing forum's verified heads and college
+// admins may change it.
+func UpdateRegistrationLimit(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := c.Locals("authUser").(middleware.AuthUser)
+		queries := db.New(database.DB)
+
+		event, err := getCollegeEvent(c, queries)
+		if err != nil {
+			return eventLookupErrorResponse(c, err)
+		}
+
+		roles, err := eventRolesOf(c.Context(), queries, authUser, event)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update registration limit"})
+		}
+		// Not eventRoleReviewer, which also covers approved staff in charge.
+		if roles&eventRoleOrganizer == 0 && authUser.Role != string(db.UserRoleCollegeAdmin) {
+			return middleware.Forbidden(c, "Only the organizers and college admins can change the registration limit.")
+		}
+		if event.Status == db.EventStatusCancelled {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":  "Cancelled events cannot be changed.",
+				"code":   "EVENT_NOT_EDITABLE",
+				"status": event.Status,
+			})
+		}
+
+		var payload RegistrationLimitPayload
+		if err := c.BodyParser(&payload); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+		}
+		var limit pgtype.Int4
+		if payload.RegistrationLimit != nil {
+			if *payload.RegistrationLimit < 1 {
+				return invalidRegistrationLimitResponse(c)
+			}
+			limit = pgtype.Int4{Int32: *payload.RegistrationLimit, Valid: true}
+		}
+
+		tx, err := database.DB.Begin(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update registration limit"})
+		}
+		defer tx.Rollback(c.Context())
+		qtx := queries.WithTx(tx)
+
+		updated, err := qtx.SetEventRegistrationLimit(c.Context(), db.SetEventRegistrationLimitParams{
+			ID:                event.ID,
+			RegistrationLimit: limit,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update registration limit"})
+		}
+
+		offers, err := registration.Promote(c.Context(), qtx, cfg, event.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update registration limit"})
+		}
+
+		if err := tx.Commit(c.Context()); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update registration limit"})
+		}
+		go registration.Notify(cfg, offers)
+
+		return c.JSON(eventResponse(updated))
+	}
+}
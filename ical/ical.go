@@ -0,0 +1,121 @@
+// Package ical renders event feeds in the iCalendar format (RFC 5545) so they
+// can be subscribed to from Google, Apple or Outlook calendars.
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	prodID = "-//Unibook//Events//EN"
+	// maxLineOctets is the longest content line RFC 5545 allows before folding.
+	maxLineOctets = 75
+)
+
+// Event is one VEVENT. UID must stay stable for the lifetime of the event and
+// Sequence must grow whenever it changes, so that subscribed calendars replace
+// their copy instead of adding a new one.
+type Event struct {
+	UID          string
+	Sequence     int
+	LastModified time.Time
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Cancelled    bool
+}
+
+// Calendar is a VCALENDAR holding a list of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Encode returns the calendar as an iCalendar document.
+func (cal Calendar) Encode() string {
+	var w writer
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", prodID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		w.line("X-WR-CALNAME", escapeText(cal.Name))
+	}
+
+	for _, event := range cal.Events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", escapeText(event.UID))
+		w.line("SEQUENCE", strconv.Itoa(event.Sequence))
+		w.line("DTSTAMP", formatTime(event.LastModified))
+		w.line("LAST-MODIFIED", formatTime(event.LastModified))
+		w.line("DTSTART", formatTime(event.Start))
+		w.line("DTEND", formatTime(event.End))
+		w.line("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			w.line("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.Location != "" {
+			w.line("LOCATION", escapeText(event.Location))
+		}
+		if event.URL != "" {
+			w.line("URL", event.URL)
+		}
+		if event.Cancelled {
+			w.line("STATUS", "CANCELLED")
+		} else {
+			w.line("STATUS", "CONFIRMED")
+		}
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.String()
+}
+
+// formatTime renders t as a UTC DATE-TIME value.
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11).
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+type writer struct {
+	strings.Builder
+}
+
+// line writes a CRLF-terminated content line, folding it so that no physical
+// line exceeds 75 octets without splitting a UTF-8 sequence.
+func (w *writer) line(name, value string) {
+	s := name + ":" + value
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts towards the limit.
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,145 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Tech Fest", "Tech Fest"},
+		{"backslash", `C:\events`, `C:\\events`},
+		{"semicolon", "a;b", `a\;b`},
+		{"comma", "Hall A, Block 2", `Hall A\, Block 2`},
+		{"newline", "line one\nline two", `line one\nline two`},
+		{"crlf", "line one\r\nline two", `line one\nline two`},
+		{"lone carriage return", "a\rb", "ab"},
+		{"escaped sequence is not unescaped", `\n`, `\\n`},
+		{"mixed", "a\\;b,\r\n", `a\\\;b\,\n`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeText(tt.in); got != tt.want {
+				t.Errorf("escapeText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		// want lists the physical lines, without their CRLF terminators.
+		want []string
+	}{
+		{
+			name:  "short",
+			value: "hello",
+			want:  []string{"SUMMARY:hello"},
+		},
+		{
+			name:  "ascii exactly 75 octets",
+			value: strings.Repeat("a", 67),
+			want:  []string{"SUMMARY:" + strings.Repeat("a", 67)},
+		},
+		{
+			name:  "ascii 76 octets",
+			value: strings.Repeat("a", 68),
+			want:  []string{"SUMMARY:" + strings.Repeat("a", 67), " a"},
+		},
+		{
+			name:  "ascii continuation space counts towards the limit",
+			value: strings.Repeat("a", 67+74+1),
+			want:  []string{"SUMMARY:" + strings.Repeat("a", 67), " " + strings.Repeat("a", 74), " a"},
+		},
+		{
+			name:  "multibyte exactly 75 octets",
+			value: "a" + strings.Repeat("é", 33),
+			want:  []string{"SUMMARY:a" + strings.Repeat("é", 33)},
+		},
+		{
+			name:  "multibyte fold on a rune boundary at 75 octets",
+			value: "a" + strings.Repeat("é", 34),
+			want:  []string{"SUMMARY:a" + strings.Repeat("é", 33), " é"},
+		},
+		{
+			name:  "multibyte fold backs off instead of splitting a rune",
+			value: strings.Repeat("é", 34),
+			want:  []string{"SUMMARY:" + strings.Repeat("é", 33), " é"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w writer
+			w.line("SUMMARY", tt.value)
+			got := w.String()
+
+			want := strings.Join(tt.want, "\r\n") + "\r\n"
+			if got != want {
+				t.Fatalf("line() = %q, want %q", got, want)
+			}
+
+			for _, physical := range strings.SplitAfter(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+				physical = strings.TrimSuffix(physical, "\r\n")
+				if len(physical) > maxLineOctets {
+					t.Errorf("physical line %q is %d octets, want at most %d", physical, len(physical), maxLineOctets)
+				}
+				if !utf8.ValidString(physical) {
+					t.Errorf("physical line %q splits a UTF-8 sequence", physical)
+				}
+			}
+
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(got, "\r\n"), "\r\n ", ""); unfolded != "SUMMARY:"+tt.value {
+				t.Errorf("unfolded line = %q, want %q", unfolded, "SUMMARY:"+tt.value)
+			}
+		})
+	}
+}
+
+func TestEncodeStatusAndSequence(t *testing.T) {
+	at := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		event Event
+		want  []string
+	}{
+		{
+			name:  "confirmed",
+			event: Event{UID: "event-1@unibook", Sequence: 0, LastModified: at, Start: at, End: at.Add(time.Hour), Summary: "Talk"},
+			want:  []string{"SEQUENCE:0\r\n", "STATUS:CONFIRMED\r\n"},
+		},
+		{
+			name:  "cancelled",
+			event: Event{UID: "event-1@unibook", Sequence: 3, LastModified: at, Start: at, End: at.Add(time.Hour), Summary: "Talk", Cancelled: true},
+			want:  []string{"SEQUENCE:3\r\n", "STATUS:CANCELLED\r\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calendar{Name: "Unibook", Events: []Event{tt.event}}.Encode()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Encode() is missing %q:\n%s", want, got)
+				}
+			}
+			for _, want := range []string{"DTSTART:20260314T093000Z\r\n", "DTEND:20260314T103000Z\r\n"} {
+				if !strings.Contains(got, want) {
+					t.Errorf("Encode() is missing %q:\n%s", want, got)
+				}
+			}
+			if strings.Count(got, "STATUS:") != 1 {
+				t.Errorf("Encode() has %d STATUS lines, want 1", strings.Count(got, "STATUS:"))
+			}
+			if !strings.HasPrefix(got, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(got, "END:VCALENDAR\r\n") {
+				t.Errorf("Encode() is not wrapped in a VCALENDAR:\n%s", got)
+			}
+		})
+	}
+}
@@ -2,28 +2,106 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"unibook-go/config"
 	"unibook-go/database"
+	"unibook-go/registration"
 	"unibook-go/routes"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+const usage = `usage: unibook <command> [arguments]
+
+commands:
+  serve                start the HTTP server (default)
+  migrate              manage database migrations (see "unibook migrate")
+  create-super-admin   create a super admin account
+  reset-password       set a new password for a user or super admin
+  list-colleges        print all colleges
+  purge-expired-otps   delete expired and used one-time codes
+  process-waitlists    pass on waitlist spots whose claim window ran out
+  seed                 create a demo college with sample accounts`
+
+// usageError marks errors caused by invalid command-line usage; they exit with status 2.
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
 func main() {
+	command := "serve"
+	args := os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	if command == "help" || command == "-h" || command == "--help" {
+		fmt.Println(usage)
+		return
+	}
+
+	// Commands that only query the database load just its settings, so they
+	// work without the server's SMTP and JWT configuration.
+	load := config.LoadDatabaseConfig
+	var run func(cfg *config.Config, args []string) error
+	switch command {
+	case "serve":
+		load = config.LoadConfig
+		run = func(cfg *config.Config, _ []string) error { return runServe(cfg) }
+	case "migrate":
+		run = runMigrate
+	case "create-super-admin":
+		run = runCreateSuperAdmin
+	case "reset-password":
+		run = runResetPassword
+	case "list-colleges":
+		run = runListColleges
+	case "purge-expired-otps":
+		run = runPurgeExpiredOtps
+	case "process-waitlists":
+		// Promotions email the people who got a spot.
+		load = config.LoadConfig
+		run = runProcessWaitlists
+	case "seed":
+		run = runSeed
+	}
+
+	var err error
+	if run == nil {
+		err = usageError{fmt.Sprintf("unknown command %q\n\n%s", command, usage)}
+	} else {
+		cfg, loadErr := load()
+		if loadErr != nil {
+			log.Fatalf("Failed to load configuration: %v", loadErr)
+		}
+		err = run(cfg, args)
+	}
 
-	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		var uErr usageError
+		if errors.As(err, &uErr) {
+			os.Exit(2)
+		}
+		os.Exit(1)
 	}
+}
+
+func runServe(cfg *config.Config) error {
+	database.Connect(cfg.DatabaseURL, cfg.MigrateOnStartup)
 
-	database.Connect(cfg.DatabaseURL)
+	// Hand on waitlist spots whose claim window ran out without waiting for
+	// someone to touch the event.
+	go registration.Sweep(context.Background(), cfg, time.Minute)
 
 	app := fiber.New()
 
-	// /api/v1/auth
-	routes.SetupAuthRoutes(app, cfg)
+	routes.Setup(app, cfg)
 
 	app.Get("/", func(c *fiber.Ctx) error {
 		if err := database.DB.Ping(context.Background()); err != nil {
@@ -41,6 +119,7 @@ func main() {
 
 	log.Printf("Server is running on http://%s", cfg.ServerAddr)
 	if err := app.Listen(cfg.ServerAddr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		return fmt.Errorf("failed to start server: %w", err)
 	}
+	return nil
 }
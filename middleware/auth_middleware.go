@@ -1,7 +1,11 @@
 package middleware
 
 import (
+	"time"
+
 	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
 
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
@@ -13,6 +17,7 @@ type AuthUser struct {
 	ID        uuid.UUID
 	Role      string
 	CollegeID *uuid.UUID
+	SessionID uuid.UUID
 }
 
 func Protected(cfg *config.Config) fiber.Handler {
@@ -27,20 +32,37 @@ func Protected(cfg *config.Config) fiber.Handler {
 			claims := token.Claims.(jwt.MapClaims)
 
 			// Parse the ID
-			id, err := uuid.Parse(claims["id"].(string))
+			idClaim, _ := claims["id"].(string)
+			id, err := uuid.Parse(idClaim)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token claims"})
+			}
+
+			// Tokens without a session are legacy, non-expiring tokens and are no longer accepted
+			sidClaim, _ := claims["sid"].(string)
+			sessionID, err := uuid.Parse(sidClaim)
 			if err != nil {
 				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid token claims"})
 			}
 
+			// Make sure the session backing this token has not been logged out
+			session, err := db.New(database.DB).GetSessionByID(c.Context(), sessionID)
+			if err != nil || session.UserID != id || session.RevokedAt.Valid || time.Now().After(session.ExpiresAt.Time) {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Session has expired or was revoked"})
+			}
+
+			role, _ := claims["role"].(string)
+
 			// Create our AuthUser struct
 			authUser := AuthUser{
-				ID:   id,
-				Role: claims["role"].(string),
+				ID:        id,
+				Role:      role,
+				SessionID: sessionID,
 			}
 
 			// Check for the optional collegeId
-			if collegeIdClaim, ok := claims["collegeId"]; ok {
-				collegeId, err := uuid.Parse(collegeIdClaim.(string))
+			if collegeIdClaim, ok := claims["collegeId"].(string); ok {
+				collegeId, err := uuid.Parse(collegeIdClaim)
 				if err == nil {
 					authUser.CollegeID = &collegeId
 				}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+
+	"unibook-go/database"
+	db "unibook-go/database/db"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RoleSuperAdmin is the role carried by super admin tokens. Super admins live in
+// their own table, so the role is not part of the db.UserRole enum.
+const RoleSuperAdmin = "super_admin"
+
+// Forbidden writes the uniform 403 envelope used by all authorization checks.
+func Forbidden(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error": message,
+		"code":  "FORBIDDEN",
+	})
+}
+
+// RequireRole only lets through users whose role is one of roles.
+// Must be attached after Protected.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser, ok := c.Locals("authUser").(AuthUser)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: Invalid or missing token"})
+		}
+
+		if !slices.Contains(roles, authUser.Role) {
+			return Forbidden(c, "You do not have permission to perform this action.")
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireSameCollege checks that the college ID in the given route param is the
+// user's own college. Super admins may access any college.
+func RequireSameCollege(param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser, ok := c.Locals("authUser").(AuthUser)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: Invalid or missing token"})
+		}
+
+		if authUser.Role == RoleSuperAdmin {
+			return c.Next()
+		}
+
+		collegeID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid college ID"})
+		}
+
+		if authUser.CollegeID == nil || *authUser.CollegeID != collegeID {
+			return Forbidden(c, "You do not have access to this college.")
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireForumHeadOf checks that the user is a verified head of the forum whose
+// ID is in the given route param.
+func RequireForumHeadOf(param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser, ok := c.Locals("authUser").(AuthUser)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: Invalid or missing token"})
+		}
+
+		if authUser.Role != string(db.UserRoleForumHead) {
+			return Forbidden(c, "Only forum heads can perform this action.")
+		}
+
+		forumID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid forum ID"})
+		}
+
+		if !IsVerifiedForumHead(c.Context(), db.New(database.DB), authUser, forumID) {
+			return Forbidden(c, "You are not a verified head of this forum.")
+		}
+
+		return c.Next()
+	}
+}
+
+// IsVerifiedForumHead reports whether user is a forum head whose headship of
+// forumID has been verified by a college admin. Handlers that take the forum
+// from the request body use it instead of RequireForumHeadOf.
+func IsVerifiedForumHead(ctx context.Context, queries *db.Queries, user AuthUser, forumID uuid.UUID) bool {
+	if user.Role != string(db.UserRoleForumHead) {
+		return false
+	}
+	forumHead, err := queries.GetForumHead(ctx, db.GetForumHeadParams{
+		UserID:  user.ID,
+		ForumID: forumID,
+	})
+	return err == nil && forumHead.IsVerified
+}
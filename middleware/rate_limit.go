@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitStore counts requests per key in fixed windows.
+type RateLimitStore interface {
+	// Hit records one request for key and returns the number of requests seen in
+	// the current window together with the time the window resets.
+	Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error)
+}
+
+// RateLimitKeyFunc extracts the value a bucket is keyed on. Returning an empty
+// string skips the bucket for this request.
+type RateLimitKeyFunc func(c *fiber.Ctx) string
+
+// ByIP keys a bucket on the client IP.
+func ByIP(c *fiber.Ctx) string {
+	return c.IP()
+}
+
+// ByEmail keys a bucket on the "email" field of the JSON body, so that a single
+// account cannot be targeted from many IPs.
+func ByEmail(c *fiber.Ctx) string {
+	var body struct {
+		Email string `json:"email"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(body.Email))
+}
+
+type RateLimiter struct {
+	store  RateLimitStore
+	limits map[string]config.RateLimit
+}
+
+// NewRateLimiter builds a limiter backed by the store selected in cfg, using the
+// bucket limits from cfg.RateLimits. The Postgres store shares counters between
+// instances; the memory store does not.
+func NewRateLimiter(cfg *config.Config) *RateLimiter {
+	if cfg.RateLimitStore == "postgres" {
+		return &RateLimiter{store: NewPostgresRateLimitStore(), limits: cfg.RateLimits}
+	}
+	return &RateLimiter{store: NewMemoryRateLimitStore(), limits: cfg.RateLimits}
+}
+
+// Limit applies the configured limit of the named bucket to each key and
+// answers 429 with a Retry-After header once it is exceeded. It panics if the
+// bucket has no configured limit.
+func (rl *RateLimiter) Limit(bucket string, keyFunc RateLimitKeyFunc) fiber.Handler {
+	limit, ok := rl.limits[bucket]
+	if !ok {
+		panic(fmt.Sprintf("rate limit bucket %q is not configured", bucket))
+	}
+	max, window := limit.Max, limit.Window
+
+	return func(c *fiber.Ctx) error {
+		key := keyFunc(c)
+		if key == "" {
+			return c.Next()
+		}
+
+		hits, resetAt, err := rl.store.Hit(c.Context(), bucket+":"+key, window)
+		if err != nil {
+			// Fail open: an unavailable store should not take the auth API down with it.
+			log.Printf("Rate limit store error for bucket %s: %v", bucket, err)
+			return c.Next()
+		}
+
+		if hits > max {
+			retryAfter := int(math.Ceil(time.Until(resetAt).Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":      "Too many requests. Please try again later.",
+				"code":       "RATE_LIMITED",
+				"retryAfter": retryAfter,
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+type memoryBucket struct {
+	hits    int
+	resetAt time.Time
+}
+
+// MemoryRateLimitStore keeps counters in process memory.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	s := &MemoryRateLimitStore{buckets: make(map[string]*memoryBucket)}
+	go s.cleanup(time.Minute)
+	return s
+}
+
+func (s *MemoryRateLimitStore) Hit(_ context.Context, key string, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	b, ok := s.buckets[key]
+	if !ok || !now.Before(b.resetAt) {
+		b = &memoryBucket{resetAt: now.Add(window)}
+		s.buckets[key] = b
+	}
+	b.hits++
+	return b.hits, b.resetAt, nil
+}
+
+func (s *MemoryRateLimitStore) cleanup(interval time.Duration) {
+	for range time.Tick(interval) {
+		now := time.Now()
+		s.mu.Lock()
+		for key, b := range s.buckets {
+			if !now.Before(b.resetAt) {
+				delete(s.buckets, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// PostgresRateLimitStore keeps counters in the rate_limit_buckets table.
+type PostgresRateLimitStore struct{}
+
+func NewPostgresRateLimitStore() *PostgresRateLimitStore {
+	s := &PostgresRateLimitStore{}
+	go s.cleanup(10 * time.Minute)
+	return s
+}
+
+func (s *PostgresRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	row, err := db.New(database.DB).HitRateLimitBucket(ctx, db.HitRateLimitBucketParams{
+		Key:           key,
+		WindowSeconds: int32(math.Ceil(window.Seconds())),
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return int(row.Hits), row.ResetAt.Time, nil
+}
+
+func (s *PostgresRateLimitStore) cleanup(interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := db.New(database.DB).DeleteExpiredRateLimitBuckets(context.Background()); err != nil {
+			log.Printf("Failed to purge expired rate limit buckets: %v", err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Package otp issues and verifies the one-time codes that are emailed to users
+// for verification flows. Codes are stored bcrypt-hashed in the otps table, one
+// active code per user and purpose.
+package otp
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"unibook-go/config"
+	db "unibook-go/database/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrInvalid is returned when the code does not match or there is no active code.
+	ErrInvalid = errors.New("otp: invalid code")
+	// ErrExpired is returned when the active code is past its expiry.
+	ErrExpired = errors.New("otp: code expired")
+	// ErrLocked is returned when too many wrong codes were entered.
+	ErrLocked = errors.New("otp: too many failed attempts")
+)
+
+// Generate returns a crypto-random numeric code with the given number of digits.
+func Generate(length int) (string, error) {
+	var sb strings.Builder
+	sb.Grow(length)
+	for range length {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(byte('0' + n.Int64()))
+	}
+	return sb.String(), nil
+}
+
+// Issue replaces any outstanding code for the user and purpose with a new one and
+// returns it in plain text so it can be emailed. It fails with ErrLocked while a
+// previous code for the same purpose is inside its lockout window.
+func Issue(ctx context.Context, queries *db.Queries, cfg *config.Config, userID uuid.UUID, purpose db.OtpPurpose) (string, error) {
+	active, err := queries.GetActiveOtp(ctx, db.GetActiveOtpParams{UserID: userID, Purpose: purpose})
+	if err == nil {
+		if active.LockedAt.Valid && time.Now().Before(active.LockedAt.Time.Add(cfg.OTPLockout)) {
+			return "", ErrLocked
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+
+	code, err := Generate(cfg.OTPLength)
+	if err != nil {
+		return "", err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(code), 10)
+	if err != nil {
+		return "", err
+	}
+
+	// CreateOtp replaces the outstanding code in the same statement; the partial
+	// unique index on otps makes concurrent issues take turns.
+	_, err = queries.CreateOtp(ctx, db.CreateOtpParams{
+		UserID:      userID,
+		Purpose:     purpose,
+		CodeHash:    string(hashed),
+		MaxAttempts: int32(cfg.OTPMaxAttempts),
+		ExpiresAt:   pgtype.Timestamp{Time: time.Now().Add(cfg.OTPTTL), Valid: true},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// Verify checks code against the user's active code for purpose and consumes it
+// on success, so each code can only be used once.
+func Verify(ctx context.Context, queries *db.Queries, userID uuid.UUID, purpose db.OtpPurpose, code string) error {
+	active, err := queries.GetActiveOtp(ctx, db.GetActiveOtpParams{UserID: userID, Purpose: purpose})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	if active.LockedAt.Valid {
+		return ErrLocked
+	}
+	if time.Now().After(active.ExpiresAt.Time) {
+		return ErrExpired
+	}
+
+	// Count the attempt before comparing so parallel guesses cannot exceed the limit.
+	attempt, err := queries.RecordOtpAttempt(ctx, active.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrLocked
+	}
+	if err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(attempt.CodeHash), []byte(code)) != nil {
+		if attempt.LockedAt.Valid {
+			return ErrLocked
+		}
+		return ErrInvalid
+	}
+
+	rows, err := queries.ConsumeOtp(ctx, attempt.ID)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrInvalid
+	}
+	return nil
+}
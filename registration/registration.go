@@ -0,0 +1,196 @@
+// Package registration holds the rules shared by event registrations and their
+// waitlist: how many spots an event has and how freed spots are offered to the
+// people waiting for them.
+package registration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"unibook-go/config"
+	"unibook-go/database"
+	db "unibook-go/database/db"
+	"unibook-go/util"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Capacity is the number of registrations an event accepts: the smaller of its
+// own limit and its venue's capacity. nil means unlimited.
+func Capacity(registrationLimit, venueCapacity pgtype.Int4) *int32 {
+	var capacity *int32
+	for _, c := range []pgtype.Int4{registrationLimit, venueCapacity} {
+		if c.Valid && (capacity == nil || c.Int32 < *capacity) {
+			capacity = &c.Int32
+		}
+	}
+	return capacity
+}
+
+// Now returns the current time the way event times are stored: as UTC wall
+// clock in a timestamp column.
+func Now() pgtype.Timestamp {
+	return pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+}
+
+// Offer is a spot held for someone who was waiting for it.
+type Offer struct {
+	EventID   uuid.UUID
+	EventName string
+	FullName  string
+	Email     string
+	ExpiresAt time.Time
+}
+
+// Promote drops the event's expired offers and offers every free spot to the
+// people who have waited longest. It locks the event row, so it must run in a
+// transaction; pass the returned offers to Notify once that has committed.
+//
+// A spot is free when it is neither registered nor held by an unexpired offer.
+func Promote(ctx context.Context, queries *db.Queries, cfg *config.Config, eventID uuid.UUID) ([]Offer, error) {
+	event, err := queries.LockEventForRegistration(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := Now()
+	_, err = queries.DeleteExpiredWaitlistOffers(ctx, db.DeleteExpiredWaitlistOffersParams{EventID: eventID, Now: now})
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status != db.EventStatusConfirmed || !now.Time.Before(event.StartTime.Time) {
+		return nil, nil
+	}
+
+	maxEntries := int32(math.MaxInt32)
+	if capacity := Capacity(event.RegistrationLimit, event.VenueCapacity); capacity != nil {
+		registered, err := queries.CountEventRegistrations(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		offered, err := queries.CountActiveWaitlistOffers(ctx, db.CountActiveWaitlistOffersParams{EventID: eventID, Now: now})
+		if err != nil {
+			return nil, err
+		}
+		free := int64(*capacity) - registered - offered
+		if free <= 0 {
+			return nil, nil
+		}
+		maxEntries = int32(min(free, math.MaxInt32))
+	}
+
+	entries, err := queries.ListNextWaitlistEntries(ctx, db.ListNextWaitlistEntriesParams{
+		EventID:    eventID,
+		MaxEntries: maxEntries,
+	})
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+
+	// Offers never outlive the start of the event.
+	expiresAt := now.Time.Add(cfg.WaitlistClaimWindow)
+	if expiresAt.After(event.StartTime.Time) {
+		expiresAt = event.StartTime.Time
+	}
+
+	ids := make([]uuid.UUID, 0, len(entries))
+	offers := make([]Offer, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+		offers = append(offers, Offer{
+			EventID:   eventID,
+			EventName: event.Name,
+			FullName:  entry.FullName,
+			Email:     entry.Email,
+			ExpiresAt: expiresAt,
+		})
+	}
+
+	_, err = queries.OfferWaitlistSpots(ctx, db.OfferWaitlistSpotsParams{
+		OfferExpiresAt: pgtype.Timestamp{Time: expiresAt, Valid: true},
+		Ids:            ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+// Notify emails everyone who was offered a spot and returns once every email
+// was handed to the mail server. Failures are logged, since the offers stand
+// either way. Request handlers run it in a goroutine.
+func Notify(cfg *config.Config, offers []Offer) {
+	for _, offer := range offers {
+		link := cfg.AppURL + "/events/" + offer.EventID.String()
+		if err := util.SendWaitlistOfferEmail(cfg, offer.Email, offer.FullName, offer.EventName, link, offer.ExpiresAt); err != nil {
+			log.Printf("Failed to email waitlist offer for event %s to %s: %v", offer.EventID, offer.Email, err)
+		}
+	}
+}
+
+// PromoteEvent runs Promote for one event in its own transaction and notifies
+// the people who were offered a spot before returning.
+func PromoteEvent(ctx context.Context, cfg *config.Config, eventID uuid.UUID) error {
+	tx, err := database.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	offers, err := Promote(ctx, db.New(database.DB).WithTx(tx), cfg, eventID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	Notify(cfg, offers)
+	return nil
+}
+
+// ProcessExpiredOffers passes on the spots of every offer that ran out. It
+// returns the number of events whose waitlist moved. An event that fails is
+// logged and skipped so it does not hold up the others; the failures are
+// returned together.
+func ProcessExpiredOffers(ctx context.Context, cfg *config.Config) (int, error) {
+	eventIDs, err := db.New(database.DB).ListEventsWithExpiredOffers(ctx, Now())
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	var errs []error
+	for _, eventID := range eventIDs {
+		if err := PromoteEvent(ctx, cfg, eventID); err != nil {
+			log.Printf("Failed to pass on expired waitlist offers for event %s: %v", eventID, err)
+			errs = append(errs, fmt.Errorf("event %s: %w", eventID, err))
+			continue
+		}
+		processed++
+	}
+	return processed, errors.Join(errs...)
+}
+
+// Sweep calls ProcessExpiredOffers every interval until ctx is cancelled.
+func Sweep(ctx context.Context, cfg *config.Config, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := ProcessExpiredOffers(ctx, cfg); err != nil {
+				log.Printf("Failed to process expired waitlist offers: %v", err)
+			}
+		}
+	}
+}
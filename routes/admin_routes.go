@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"unibook-go/config"
+	"unibook-go/handlers"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupAdminRoutes(app *fiber.App, cfg *config.Config) {
+	api := app.Group("/api/v1")
+	admin := api.Group("/admin", middleware.Protected(cfg), middleware.RequireRole(middleware.RoleSuperAdmin))
+
+	admin.Get("/colleges", handlers.ListColleges)
+	admin.Post("/colleges", handlers.CreateCollege)
+	admin.Post("/colleges/onboard", handlers.OnboardCollege(cfg))
+	admin.Get("/colleges/:id", handlers.GetCollege)
+	admin.Patch("/colleges/:id", handlers.UpdateCollege)
+	admin.Delete("/colleges/:id", handlers.DeleteCollege)
+	admin.Post("/colleges/:id/admins/:userId/resend-invite", handlers.ResendAdminInvite(cfg))
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"unibook-go/config"
+	"unibook-go/handlers"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupCalendarRoutes(app *fiber.App, cfg *config.Config) {
+	api := app.Group("/api/v1")
+	limiter := middleware.NewRateLimiter(cfg)
+
+	// Token management needs a session; the feeds themselves are fetched by
+	// calendar apps and authenticate with the secret token in the URL instead.
+	api.Post("/calendar/token", middleware.Protected(cfg), collegeMember(), handlers.RotateCalendarToken)
+	api.Delete("/calendar/token", middleware.Protected(cfg), collegeMember(), handlers.RevokeCalendarToken)
+
+	feedLimit := limiter.Limit("calendar-feed-ip", middleware.ByIP)
+	api.Get("/calendar/:token/college.ics", feedLimit, handlers.CollegeCalendarFeed(cfg))
+	api.Get("/calendar/:token/forums/:forumId.ics", feedLimit, handlers.ForumCalendarFeed(cfg))
+	api.Get("/calendar/:token/personal.ics", feedLimit, handlers.PersonalCalendarFeed(cfg))
+}
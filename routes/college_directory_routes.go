@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"fmt"
+
+	"unibook-go/config"
+	"unibook-go/handlers"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cache"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// SetupCollegeDirectoryRoutes registers the public college directory used by
+// the registration form. Responses are cached per URL for
+// cfg.CollegeDirectoryCacheTTL, since the directory only changes when colleges
+// are onboarded or edited. The URL carries free-form search terms, so the cache
+// is capped at cfg.CollegeDirectoryCacheMaxBytes.
+func SetupCollegeDirectoryRoutes(app *fiber.App, cfg *config.Config) {
+	api := app.Group("/api/v1")
+	limiter := middleware.NewRateLimiter(cfg)
+
+	// Attached per route rather than on a /colleges group, which would also
+	// match /colleges/:collegeId/forums.
+	public := []fiber.Handler{
+		limiter.Limit("college-directory-ip", middleware.ByIP),
+		func(c *fiber.Ctx) error {
+			c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(cfg.CollegeDirectoryCacheTTL.Seconds())))
+			return c.Next()
+		},
+		cache.New(cache.Config{
+			Expiration: cfg.CollegeDirectoryCacheTTL,
+			MaxBytes:   uint(cfg.CollegeDirectoryCacheMaxBytes),
+			KeyGenerator: func(c *fiber.Ctx) string {
+				return utils.CopyString(c.OriginalURL())
+			},
+		}),
+	}
+
+	api.Get("/colleges", append(public, handlers.ListPublicColleges)...)
+	api.Get("/colleges/lookup", append(public, handlers.LookupCollegeByEmail)...)
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"unibook-go/config"
+	db "unibook-go/database/db"
+	"unibook-go/handlers"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupCollegeRoutes registers the endpoints college admins use to manage their
+// own college.
+func SetupCollegeRoutes(app *fiber.App, cfg *config.Config) {
+	api := app.Group("/api/v1")
+	// The middleware is attached per route rather than to the group: group
+	// middleware matches by path prefix, so it would also run for the public
+	// /colleges routes.
+	college := api.Group("/college")
+	protected := middleware.Protected(cfg)
+	collegeAdmin := middleware.RequireRole(string(db.UserRoleCollegeAdmin))
+
+	college.Get("/users/pending", protected, collegeAdmin, handlers.ListPendingUsers)
+	college.Post("/users/approve", protected, collegeAdmin, handlers.ApproveUsers(cfg))
+	college.Post("/users/:id/approve", protected, collegeAdmin, handlers.ApproveUser(cfg))
+	college.Post("/users/:id/reject", protected, collegeAdmin, handlers.RejectUser(cfg))
+
+	college.Get("/forum-heads", protected, collegeAdmin, handlers.ListForumHeads)
+	college.Post("/forums/:forumId/heads/:userId/verify", protected, collegeAdmin, handlers.VerifyForumHead)
+	college.Post("/forums/:forumId/heads/:userId/revoke", protected, collegeAdmin, handlers.RevokeForumHead)
+	college.Post("/forums/:forumId/heads/:userId/reassign", protected, collegeAdmin, handlers.ReassignForumHead)
+}
+
+// collegeMember lets through users that belong to a college, i.e. everyone
+// except super admins.
+func collegeMember() fiber.Handler {
+	return middleware.RequireRole(
+		string(db.UserRoleCollegeAdmin),
+		string(db.UserRoleForumHead),
+		string(db.UserRoleTeacher),
+		string(db.UserRoleStudent),
+	)
+}
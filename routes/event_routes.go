@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"unibook-go/config"
+	db "unibook-go/database/db"
+	"unibook-go/handlers"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupEventRoutes(app *fiber.App, cfg *config.Config) {
+	api := app.Group("/api/v1")
+	events := api.Group("/events", middleware.Protected(cfg), collegeMember())
+
+	events.Get("/", handlers.ListEvents)
+	events.Post("/", handlers.CreateEvent)
+	events.Get("/:id", handlers.GetEvent)
+	events.Patch("/:id", handlers.UpdateEvent)
+
+	events.Post("/:id/submit", handlers.SubmitEvent)
+	events.Post("/:id/withdraw", handlers.WithdrawEvent)
+	events.Post("/:id/confirm", handlers.ConfirmEvent)
+	events.Post("/:id/cancel", handlers.CancelEvent)
+
+	events.Post("/:id/collaborators", handlers.InviteCollaborator)
+	events.Delete("/:id/collaborators/:collaboratorId", handlers.RemoveCollaborator)
+
+	events.Post("/:id/staff", handlers.RequestStaff)
+	events.Delete("/:id/staff/:assignmentId", handlers.RemoveStaff)
+
+	events.Post("/:id/registration", handlers.RegisterForEvent)
+	events.Delete("/:id/registration", handlers.CancelRegistration(cfg))
+	events.Get("/:id/registrations", handlers.ListRegistrations)
+	events.Get("/:id/registrations/export", handlers.ExportRegistrations)
+	events.Put("/:id/registration-limit", handlers.UpdateRegistrationLimit(cfg))
+
+	events.Get("/:id/waitlist", handlers.ListWaitlist)
+	events.Post("/:id/waitlist", handlers.JoinWaitlist(cfg))
+	events.Delete("/:id/waitlist", handlers.LeaveWaitlist(cfg))
+
+	events.Get("/:id/registration/ticket.png", handlers.GetTicketQR(cfg))
+	events.Post("/:id/check-in", handlers.CheckInAttendee(cfg))
+	events.Get("/:id/check-ins", handlers.GetCheckInStats)
+
+	forumHead := middleware.RequireRole(string(db.UserRoleForumHead))
+	collaborations := api.Group("/collaborations", middleware.Protected(cfg), forumHead)
+	collaborations.Get("/", handlers.ListCollaborationInvites)
+	collaborations.Post("/:id/accept", handlers.AcceptCollaboration)
+	collaborations.Post("/:id/reject", handlers.RejectCollaboration)
+
+	teacher := middleware.RequireRole(string(db.UserRoleTeacher))
+	staffRequests := api.Group("/staff-requests", middleware.Protected(cfg), teacher)
+	staffRequests.Get("/", handlers.ListStaffRequests)
+	staffRequests.Post("/:id/accept", handlers.AcceptStaffRequest)
+	staffRequests.Post("/:id/decline", handlers.DeclineStaffRequest)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"unibook-go/config"
+	db "unibook-go/database/db"
+	"unibook-go/handlers"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupForumRoutes(app *fiber.App, cfg *config.Config) {
+	api := app.Group("/api/v1")
+
+	// Public, so that forum heads can pick their forum while registering.
+	api.Get("/colleges/:collegeId/forums", handlers.ListPublicForums)
+
+	forums := api.Group("/forums", middleware.Protected(cfg), collegeMember())
+	collegeAdmin := middleware.RequireRole(string(db.UserRoleCollegeAdmin))
+
+	forums.Get("/", handlers.ListForums)
+	forums.Get("/:id", handlers.GetForum)
+	forums.Post("/", collegeAdmin, handlers.CreateForum)
+	forums.Patch("/:id", collegeAdmin, handlers.UpdateForum)
+	forums.Post("/:id/archive", collegeAdmin, handlers.ArchiveForum)
+	forums.Post("/:id/unarchive", collegeAdmin, handlers.UnarchiveForum)
+	forums.Delete("/:id", collegeAdmin, handlers.DeleteForum)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"unibook-go/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Setup registers every API route on app.
+func Setup(app *fiber.App, cfg *config.Config) {
+	// /api/v1/auth
+	SetupAuthRoutes(app, cfg)
+	// /api/v1/admin
+	SetupAdminRoutes(app, cfg)
+	// /api/v1/college
+	SetupCollegeRoutes(app, cfg)
+	// /api/v1/colleges
+	SetupCollegeDirectoryRoutes(app, cfg)
+	// /api/v1/forums
+	SetupForumRoutes(app, cfg)
+	// /api/v1/events
+	SetupEventRoutes(app, cfg)
+	// /api/v1/venues
+	SetupVenueRoutes(app, cfg)
+	// /api/v1/calendar
+	SetupCalendarRoutes(app, cfg)
+}
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"unibook-go/config"
+	"unibook-go/database"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTestApp registers every route the way runServe does. Handlers query the
+// database named by TEST_DATABASE_URL; without one they point at an address
+// nothing listens on, so requests that reach a handler fail with 500 instead
+// of succeeding.
+func newTestApp(t *testing.T) (*fiber.App, bool) {
+	t.Helper()
+
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	haveDB := dbURL != ""
+	if !haveDB {
+		dbURL = "postgres://unibook@127.0.0.1:1/unibook?connect_timeout=1"
+	}
+	pool, err := pgxpool.New(context.Background(), dbURL)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	database.DB = pool
+
+	cfg := &config.Config{
+		JWTSecret:                     "test-secret",
+		AppURL:                        "http://localhost:3000",
+		AccessTokenTTL:                15 * time.Minute,
+		CollegeDirectoryCacheTTL:      time.Minute,
+		CollegeDirectoryCacheMaxBytes: 1 << 20,
+		WaitlistClaimWindow:           24 * time.Hour,
+		RateLimitStore:                "memory",
+		RateLimits:                    config.DefaultRateLimits(),
+	}
+
+	app := fiber.New()
+	Setup(app, cfg)
+	return app, haveDB
+}
+
+// TestPublicRoutesAllowAnonymous guards against group middleware, which Fiber
+// matches by path prefix, leaking onto public routes that share a prefix with
+// a protected group.
+func TestPublicRoutesAllowAnonymous(t *testing.T) {
+	app, haveDB := newTestApp(t)
+
+	paths := []string{
+		"/api/v1/colleges",
+		"/api/v1/colleges/lookup?email=a@b.edu",
+		"/api/v1/colleges/00000000-0000-0000-0000-000000000001/forums",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil), -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if haveDB {
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("GET %s: status %d, want %d", path, resp.StatusCode, http.StatusOK)
+				}
+				return
+			}
+			if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+				t.Fatalf("GET %s: status %d, want the request to reach its handler", path, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -12,12 +12,40 @@ func SetupAuthRoutes(app *fiber.App, cfg *config.Config) {
 	api := app.Group("/api/v1")
 	auth := api.Group("/auth")
 
-	auth.Post("/register", handlers.RegisterUser(cfg))
-	auth.Post("/verify-email", handlers.VerifyOtpAndLogin(cfg))
-	auth.Post("/login", handlers.Login(cfg))
-	auth.Post("/resend-otp", handlers.ResendOtp(cfg))
-	auth.Post("/forgot-password", handlers.ForgotPassword(cfg))
-	auth.Post("/verify-reset-otp", handlers.VerifyResetOtp)
-	auth.Post("/reset-password", handlers.ResetPassword)
+	limiter := middleware.NewRateLimiter(cfg)
+
+	auth.Post("/register",
+		limiter.Limit("register-ip", middleware.ByIP),
+		limiter.Limit("register-email", middleware.ByEmail),
+		handlers.RegisterUser(cfg))
+	auth.Post("/verify-email",
+		limiter.Limit("verify-email-ip", middleware.ByIP),
+		limiter.Limit("verify-email-email", middleware.ByEmail),
+		handlers.VerifyOtpAndLogin(cfg))
+	auth.Post("/login",
+		limiter.Limit("login-ip", middleware.ByIP),
+		limiter.Limit("login-email", middleware.ByEmail),
+		handlers.Login(cfg))
+	auth.Post("/resend-otp",
+		limiter.Limit("resend-otp-ip", middleware.ByIP),
+		limiter.Limit("resend-otp-email", middleware.ByEmail),
+		handlers.ResendOtp(cfg))
+	auth.Post("/forgot-password",
+		limiter.Limit("forgot-password-ip", middleware.ByIP),
+		limiter.Limit("forgot-password-email", middleware.ByEmail),
+		handlers.ForgotPassword(cfg))
+	auth.Post("/verify-reset-otp",
+		limiter.Limit("verify-reset-otp-ip", middleware.ByIP),
+		limiter.Limit("verify-reset-otp-email", middleware.ByEmail),
+		handlers.VerifyResetOtp(cfg))
+	auth.Post("/reset-password",
+		limiter.Limit("reset-password-ip", middleware.ByIP),
+		handlers.ResetPassword(cfg))
+	auth.Post("/set-password",
+		limiter.Limit("set-password-ip", middleware.ByIP),
+		handlers.SetPassword(cfg))
+	auth.Post("/refresh", limiter.Limit("refresh-ip", middleware.ByIP), handlers.RefreshToken(cfg))
+	auth.Post("/logout", middleware.Protected(cfg), handlers.Logout)
+	auth.Post("/logout-all", middleware.Protected(cfg), handlers.LogoutAll)
 	auth.Get("/me", middleware.Protected(cfg), handlers.GetMe)
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"unibook-go/config"
+	db "unibook-go/database/db"
+	"unibook-go/handlers"
+	"unibook-go/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupVenueRoutes(app *fiber.App, cfg *config.Config) {
+	api := app.Group("/api/v1")
+	venues := api.Group("/venues", middleware.Protected(cfg), collegeMember())
+	collegeAdmin := middleware.RequireRole(string(db.UserRoleCollegeAdmin))
+
+	venues.Get("/", handlers.ListVenues)
+	venues.Get("/:id", handlers.GetVenue)
+	venues.Get("/:id/availability", handlers.GetVenueAvailability)
+	venues.Post("/", collegeAdmin, handlers.CreateVenue)
+	venues.Patch("/:id", collegeAdmin, handlers.UpdateVenue(cfg))
+	venues.Delete("/:id", collegeAdmin, handlers.DeleteVenue)
+}
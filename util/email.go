@@ -2,14 +2,17 @@ package util
 
 import (
 	"fmt"
+	"html"
 	"log"
+	"time"
 
 	"unibook-go/config"
 
 	mail "github.com/xhit/go-simple-mail/v2"
 )
 
-func SendOtpEmail(cfg *config.Config, userEmail string, otp string) error {
+// sendEmail delivers a single HTML email through the configured SMTP server.
+func sendEmail(cfg *config.Config, to string, subject string, htmlBody string) error {
 	server := mail.NewSMTPClient()
 	server.Host = cfg.SMTPHost
 	server.Port = cfg.SMTPPort
@@ -25,9 +28,20 @@ func SendOtpEmail(cfg *config.Config, userEmail string, otp string) error {
 
 	email := mail.NewMSG()
 	email.SetFrom(fmt.Sprintf("Unibook <%s>", cfg.EmailFrom)).
-		AddTo(userEmail).
-		SetSubject("Your Unibook Verification Code")
+		AddTo(to).
+		SetSubject(subject)
+	email.SetBody(mail.TextHTML, htmlBody)
+
+	if err := email.Send(smtpClient); err != nil {
+		log.Printf("Failed to send %q email to %s: %v", subject, to, err)
+		return err
+	}
+
+	log.Printf("Successfully sent %q email to %s", subject, to)
+	return nil
+}
 
+func SendOtpEmail(cfg *config.Config, userEmail string, otp string) error {
 	htmlBody := fmt.Sprintf(`
       <div style="background-color: #ffffff; color: #000000; font-family: Arial, sans-serif; padding: 20px; text-align: center;">
         <h2 style="color: #000000;">Your Verification Code</h2>
@@ -35,15 +49,68 @@ func SendOtpEmail(cfg *config.Config, userEmail string, otp string) error {
         <div style="font-size: 36px; font-weight: bold; letter-spacing: 8px; margin: 20px 0; color: #000000;">
           %s
         </div>
-        <p style="color: #555555; font-size: 12px;">This code will expire in 10 minutes.</p>
-      </div>`, otp)
-	email.SetBody(mail.TextHTML, htmlBody)
+        <p style="color: #555555; font-size: 12px;">This code will expire in %d minutes.</p>
+      </div>`, otp, int(cfg.OTPTTL.Minutes()))
 
-	if err := email.Send(smtpClient); err != nil {
-		log.Printf("Failed to send OTP email to %s: %v", userEmail, err)
-		return err
+	return sendEmail(cfg, userEmail, "Your Unibook Verification Code", htmlBody)
+}
+
+// SendInvitationEmail invites a newly onboarded college admin to set their password.
+func SendInvitationEmail(cfg *config.Config, userEmail string, fullName string, collegeName string, link string) error {
+	htmlBody := fmt.Sprintf(`
+      <div style="background-color: #ffffff; color: #000000; font-family: Arial, sans-serif; padding: 20px; text-align: center;">
+        <h2 style="color: #000000;">Welcome to Unibook, %s</h2>
+        <p style="color: #333333;">You have been added as the administrator of <strong>%s</strong>.</p>
+        <p style="color: #333333;">Set your password to activate your account.</p>
+        <a href="%s" style="display: inline-block; background-color: #000000; color: #ffffff; padding: 12px 24px; margin: 20px 0; text-decoration: none; border-radius: 4px;">
+          Set your password
+        </a>
+        <p style="color: #555555; font-size: 12px;">This link will expire in %d hours.</p>
+      </div>`, html.EscapeString(fullName), html.EscapeString(collegeName), html.EscapeString(link), int(cfg.InviteTTL.Hours()))
+
+	return sendEmail(cfg, userEmail, "You're invited to Unibook", htmlBody)
+}
+
+// SendApprovalDecisionEmail tells a user whether their college admin approved their account.
+func SendApprovalDecisionEmail(cfg *config.Config, userEmail string, fullName string, approved bool, reason string) error {
+	heading := "Your account has been approved"
+	message := "Your college admin has approved your Unibook account. You can now log in."
+	subject := "Your Unibook account has been approved"
+	if !approved {
+		heading = "Your account request was declined"
+		message = "Your college admin has declined your Unibook account request."
+		subject = "Your Unibook account request was declined"
 	}
 
-	log.Printf("Successfully sent OTP email to %s", userEmail)
-	return nil
+	reasonBlock := ""
+	if reason != "" {
+		reasonBlock = fmt.Sprintf(`
+        <p style="color: #333333;"><strong>Reason:</strong> %s</p>`, html.EscapeString(reason))
+	}
+
+	htmlBody := fmt.Sprintf(`
+      <div style="background-color: #ffffff; color: #000000; font-family: Arial, sans-serif; padding: 20px; text-align: center;">
+        <h2 style="color: #000000;">%s</h2>
+        <p style="color: #333333;">Hi %s,</p>
+        <p style="color: #333333;">%s</p>%s
+      </div>`, heading, html.EscapeString(fullName), message, reasonBlock)
+
+	return sendEmail(cfg, userEmail, subject, htmlBody)
+}
+
+// SendWaitlistOfferEmail tells someone on an event's waitlist that a spot opened
+// up for them and until when they can claim it.
+func SendWaitlistOfferEmail(cfg *config.Config, userEmail string, fullName string, eventName string, link string, expiresAt time.Time) error {
+	htmlBody := fmt.Sprintf(`
+      <div style="background-color: #ffffff; color: #000000; font-family: Arial, sans-serif; padding: 20px; text-align: center;">
+        <h2 style="color: #000000;">A spot opened up for you</h2>
+        <p style="color: #333333;">Hi %s,</p>
+        <p style="color: #333333;">You have moved off the waitlist for <strong>%s</strong>. Register to claim your spot.</p>
+        <a href="%s" style="display: inline-block; background-color: #000000; color: #ffffff; padding: 12px 24px; margin: 20px 0; text-decoration: none; border-radius: 4px;">
+          Claim your spot
+        </a>
+        <p style="color: #555555; font-size: 12px;">The spot is held for you until %s UTC, after which it goes to the next person in line.</p>
+      </div>`, html.EscapeString(fullName), html.EscapeString(eventName), html.EscapeString(link), expiresAt.UTC().Format("Jan 2, 2006 15:04"))
+
+	return sendEmail(cfg, userEmail, "A spot opened up for "+eventName, htmlBody)
 }
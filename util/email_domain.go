@@ -0,0 +1,42 @@
+package util
+
+import "strings"
+
+// EmailDomain returns the lower-cased domain part of an email address, without
+// a trailing dot. Addresses with no local part or more than one "@" are rejected.
+func EmailDomain(email string) (string, bool) {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" || strings.Contains(domain, "@") {
+		return "", false
+	}
+	domain = normalizeEmailDomain(domain)
+	if domain == "" {
+		return "", false
+	}
+	return domain, true
+}
+
+// DomainAllowed reports whether domain equals one of the allowed domains or is a
+// subdomain of one (e.g. "cs.college.edu" is allowed by "college.edu").
+func DomainAllowed(domain string, allowed []string) bool {
+	domain = normalizeEmailDomain(domain)
+	if domain == "" {
+		return false
+	}
+	for _, a := range allowed {
+		a = normalizeEmailDomain(a)
+		if a == "" {
+			continue
+		}
+		if domain == a || strings.HasSuffix(domain, "."+a) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeEmailDomain lower-cases a domain and drops the trailing dot of a
+// fully qualified name, so "College.EDU." and "college.edu" compare equal.
+func normalizeEmailDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
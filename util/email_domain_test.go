@@ -0,0 +1,63 @@
+package util
+
+import "testing"
+
+func TestEmailDomain(t *testing.T) {
+	tests := []struct {
+		email  string
+		want   string
+		wantOK bool
+	}{
+		{"student@college.edu", "college.edu", true},
+		{"Student@College.EDU", "college.edu", true},
+		{"student@cs.college.edu", "cs.college.edu", true},
+		{"student@college.edu.", "college.edu", true},
+		{"student@college.edu ", "college.edu", true},
+		{"student.college.edu", "", false},
+		{"", "", false},
+		{"@college.edu", "", false},
+		{"student@", "", false},
+		{"student@.", "", false},
+		{"student@evil.com@college.edu", "", false},
+		{"student@@college.edu", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			got, ok := EmailDomain(tt.email)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("EmailDomain(%q) = %q, %v, want %q, %v", tt.email, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDomainAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		allowed []string
+		want    bool
+	}{
+		{"exact", "college.edu", []string{"college.edu"}, true},
+		{"case folding", "College.Edu", []string{"COLLEGE.edu"}, true},
+		{"subdomain", "cs.college.edu", []string{"college.edu"}, true},
+		{"nested subdomain", "ai.cs.college.edu", []string{"college.edu"}, true},
+		{"lookalike suffix", "evilcollege.edu", []string{"college.edu"}, false},
+		{"allowed is a subdomain", "college.edu", []string{"cs.college.edu"}, false},
+		{"different tld", "college.edu.evil.com", []string{"college.edu"}, false},
+		{"trailing dot on domain", "college.edu.", []string{"college.edu"}, true},
+		{"trailing dot on allowed", "college.edu", []string{"college.edu."}, true},
+		{"second allowed domain", "alumni.org", []string{"college.edu", "alumni.org"}, true},
+		{"padded allowed domain", "college.edu", []string{" college.edu "}, true},
+		{"empty allow-list", "college.edu", nil, false},
+		{"blank allowed domains", "college.edu", []string{"", " "}, false},
+		{"empty domain", "", []string{"college.edu"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DomainAllowed(tt.domain, tt.allowed); got != tt.want {
+				t.Errorf("DomainAllowed(%q, %q) = %v, want %v", tt.domain, tt.allowed, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"unibook-go/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// GenerateAccessToken signs a short-lived HS256 access token bound to a session.
+// collegeID is nil for super admins.
+func GenerateAccessToken(cfg *config.Config, userID uuid.UUID, role string, collegeID *uuid.UUID, sessionID uuid.UUID) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"id":   userID.String(),
+		"role": role,
+		"sid":  sessionID.String(),
+		"iat":  now.Unix(),
+		"exp":  now.Add(cfg.AccessTokenTTL).Unix(),
+	}
+	if collegeID != nil {
+		claims["collegeId"] = collegeID.String()
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.JWTSecret))
+}
+
+// GenerateSecureToken returns a URL-safe random string built from n random bytes.
+func GenerateSecureToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex SHA-256 digest of a high-entropy token. Only the
+// digest is stored so a database leak does not expose usable refresh tokens.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+const (
+	resetTicketPurpose  = "password_reset"
+	inviteTicketPurpose = "invite"
+)
+
+// ErrInvalidTicket is returned for password tickets that are malformed, expired
+// or were issued for something else.
+var ErrInvalidTicket = errors.New("invalid password ticket")
+
+// GenerateResetTicket signs a short-lived ticket that allows one password reset.
+// It embeds a fingerprint of the current password hash, so the ticket stops
+// working as soon as the password has been changed.
+func GenerateResetTicket(cfg *config.Config, userID uuid.UUID, passwordHash string) (string, error) {
+	return generatePasswordTicket(cfg, resetTicketPurpose, cfg.ResetTicketTTL, userID, passwordHash)
+}
+
+// ParseResetTicket validates a reset ticket and returns the user ID and password
+// hash fingerprint it was issued for.
+func ParseResetTicket(cfg *config.Config, ticket string) (uuid.UUID, string, error) {
+	return parsePasswordTicket(cfg, resetTicketPurpose, ticket)
+}
+
+// GenerateInviteTicket signs the ticket behind an invitation's set-password link.
+// Like reset tickets it is bound to the current password hash and is single-use.
+func GenerateInviteTicket(cfg *config.Config, userID uuid.UUID, passwordHash string) (string, error) {
+	return generatePasswordTicket(cfg, inviteTicketPurpose, cfg.InviteTTL, userID, passwordHash)
+}
+
+// ParseInviteTicket validates an invitation ticket.
+func ParseInviteTicket(cfg *config.Config, ticket string) (uuid.UUID, string, error) {
+	return parsePasswordTicket(cfg, inviteTicketPurpose, ticket)
+}
+
+func generatePasswordTicket(cfg *config.Config, purpose string, ttl time.Duration, userID uuid.UUID, passwordHash string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub":     userID.String(),
+		"purpose": purpose,
+		"fp":      HashToken(passwordHash),
+		"iat":     now.Unix(),
+		"exp":     now.Add(ttl).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.JWTSecret))
+}
+
+func parsePasswordTicket(cfg *config.Config, purpose string, ticket string) (uuid.UUID, string, error) {
+	token, err := jwt.Parse(ticket, func(t *jwt.Token) (interface{}, error) {
+		return []byte(cfg.JWTSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidTicket
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != purpose {
+		return uuid.Nil, "", ErrInvalidTicket
+	}
+
+	sub, _ := claims["sub"].(string)
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidTicket
+	}
+
+	fingerprint, _ := claims["fp"].(string)
+	if fingerprint == "" {
+		return uuid.Nil, "", ErrInvalidTicket
+	}
+
+	return userID, fingerprint, nil
+}
+
+const eventTicketPurpose = "event_ticket"
+
+// ErrInvalidEventTicket is returned for event tickets that are malformed,
+// expired or not signed by us.
+var ErrInvalidEventTicket = errors.New("invalid event ticket")
+
+// GenerateEventTicket signs the ticket encoded in a registration's QR code. It
+// names the registration rather than the user, so cancelling the registration
+// voids the ticket.
+func GenerateEventTicket(cfg *config.Config, registrationID uuid.UUID, eventID uuid.UUID, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":     registrationID.String(),
+		"evt":     eventID.String(),
+		"purpose": eventTicketPurpose,
+		"iat":     time.Now().Unix(),
+		"exp":     expiresAt.Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.JWTSecret))
+}
+
+// ParseEventTicket verifies an event ticket and returns the registration and
+// event it was issued for.
+func ParseEventTicket(cfg *config.Config, ticket string) (registrationID uuid.UUID, eventID uuid.UUID, err error) {
+	token, err := jwt.Parse(ticket, func(t *jwt.Token) (interface{}, error) {
+		return []byte(cfg.JWTSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidEventTicket
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != eventTicketPurpose {
+		return uuid.Nil, uuid.Nil, ErrInvalidEventTicket
+	}
+
+	sub, _ := claims["sub"].(string)
+	evt, _ := claims["evt"].(string)
+	registrationID, err = uuid.Parse(sub)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidEventTicket
+	}
+	eventID, err = uuid.Parse(evt)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidEventTicket
+	}
+
+	return registrationID, eventID, nil
+}